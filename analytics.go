@@ -1,12 +1,15 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -17,13 +20,32 @@ type AnalyticsPayload struct {
 	Event   string `json:"event"`
 }
 
-func getOrCreateAppId() string {
+// telemetryTimeout bounds the init event so a slow analytics server never
+// holds anything up.
+const telemetryTimeout = 5 * time.Second
+
+// TelemetryInfo describes the init event exactly as it would be sent now.
+// AppID is empty when no app ID is stored; one is created on the next send.
+type TelemetryInfo struct {
+	Enabled  bool             `json:"enabled"`
+	Endpoint string           `json:"endpoint"`
+	Payload  AnalyticsPayload `json:"payload"`
+}
+
+func getAppIdPath() (string, error) {
 	settingsDir, err := getLocalAppDataDir("warno-replays-analyser")
 	if err != nil {
-		fmt.Printf("Failed to get cache directory: %v\n", err)
+		return "", fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	return filepath.Join(settingsDir, "id.bin"), nil
+}
+
+// readAppId returns the stored app ID, or "" when there is none.
+func readAppId() string {
+	idPath, err := getAppIdPath()
+	if err != nil {
 		return ""
 	}
-	idPath := fmt.Sprintf("%s/id.bin", settingsDir)
 
 	if data, err := os.ReadFile(idPath); err == nil && len(data) == 16 {
 		id, err := uuid.FromBytes(data)
@@ -32,24 +54,93 @@ func getOrCreateAppId() string {
 		}
 	}
 
+	return ""
+}
+
+func createAppId() (string, error) {
+	idPath, err := getAppIdPath()
+	if err != nil {
+		return "", err
+	}
+
 	id := uuid.New()
-	err = os.MkdirAll(filepath.Dir(idPath), os.ModePerm)
+	if err := writeFileAtomic(idPath, id[:], 0644); err != nil {
+		return "", fmt.Errorf("failed to write UUID file: %w", err)
+	}
+
+	return id.String(), nil
+}
+
+func getOrCreateAppId() string {
+	if id := readAppId(); id != "" {
+		return id
+	}
+
+	id, err := createAppId()
 	if err != nil {
-		fmt.Printf("Failed to create directory: %v\n", err)
+		fmt.Printf("%v\n", err)
 		return ""
 	}
 
-	err = os.WriteFile(idPath, id[:], 0644)
+	return id
+}
+
+func (a *App) telemetryEnabled() bool {
+	settings, err := a.GetSettings()
 	if err != nil {
-		fmt.Printf("Failed to write UUID file: %v\n", err)
-		return ""
+		// Unreadable settings may hold an opt-out; do not send anything.
+		log.Printf("Skipping telemetry, settings could not be read: %v", err)
+		return false
+	}
+	return !settings.TelemetryDisabled
+}
+
+// GetTelemetryPayload shows what sendAppInitEvent posts and where, without
+// sending or creating anything.
+func (a *App) GetTelemetryPayload() TelemetryInfo {
+	endpoint := ""
+	if apiUrl != "" {
+		endpoint = apiUrl + "/analytics"
+	}
+
+	return TelemetryInfo{
+		Enabled:  a.telemetryEnabled() && apiUrl != "" && apiKey != "",
+		Endpoint: endpoint,
+		Payload: AnalyticsPayload{
+			AppID:   readAppId(),
+			Version: version,
+			Event:   "init",
+		},
+	}
+}
+
+// RegenerateAppId replaces the stored app ID with a new random one, so later
+// events can no longer be linked to earlier ones.
+func (a *App) RegenerateAppId() (string, error) {
+	return createAppId()
+}
+
+// DeleteAppId removes the stored app ID. While telemetry is enabled a new ID
+// is created on the next start.
+func (a *App) DeleteAppId() error {
+	idPath, err := getAppIdPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(idPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete app ID: %w", err)
 	}
 
-	return id.String()
+	return nil
 }
 
-func sendAppInitEvent() {
-	appID := getOrCreateAppId()
+// sendAppInitEvent posts the init event unless telemetry is disabled. It is
+// run in its own goroutine from startup.
+func (a *App) sendAppInitEvent() {
+	if !a.telemetryEnabled() {
+		return
+	}
 
 	if apiUrl == "" || apiKey == "" {
 		log.Println("Error: API_URL or API_KEY is not set")
@@ -57,7 +148,7 @@ func sendAppInitEvent() {
 	}
 
 	payload := AnalyticsPayload{
-		AppID:   appID,
+		AppID:   getOrCreateAppId(),
 		Version: version,
 		Event:   "init",
 	}
@@ -68,12 +159,18 @@ func sendAppInitEvent() {
 		return
 	}
 
-	headers := map[string]string{
-		"Authorization": "Bearer " + apiKey,
-		"Content-Type":  "application/json",
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", apiUrl+"/analytics", bytes.NewReader(data))
+	if err != nil {
+		fmt.Printf("Failed to create analytics request: %v\n", err)
+		return
 	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := makeRequest("POST", apiUrl+"/analytics", data, headers)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		fmt.Printf("Failed to send analytics event: %v\n", err)
 		return
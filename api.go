@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"strconv"
 	"time"
 )
 
@@ -33,6 +32,7 @@ type RankedReplayInput struct {
 	Player1Division     int        `json:"player1Division"`
 	Player2Division     int        `json:"player2Division"`
 	Map                 string     `json:"map"`
+	Version             string     `json:"version,omitempty"`
 	Duration            int        `json:"duration"`
 	WinnerPlayerEugenID *uint      `json:"winnerPlayerEugenId"`
 	SubmittedByEugenID  uint       `json:"submittedByEugenId"`
@@ -91,6 +91,13 @@ type DivisionVsOpponentStats struct {
 	Losses           int     `json:"losses"`
 	Draws            int     `json:"draws"`
 	WinRate          float64 `json:"winRate"`
+	// WinRateLow and WinRateHigh bound the winrate over decisive games (95%
+	// Wilson interval), DrawAdjustedWinRate counts draws as half a win. All
+	// three are fractions in 0..1.
+	WinRateLow          float64 `json:"winRateLow"`
+	WinRateHigh         float64 `json:"winRateHigh"`
+	DrawAdjustedWinRate float64 `json:"drawAdjustedWinRate"`
+	Confidence          string  `json:"confidence"`
 }
 
 type DivisionOnMapGroup struct {
@@ -109,57 +116,77 @@ type DivisionOnMapVsDivisionGroup struct {
 }
 
 type DivisionOnMapVsOpponentStats struct {
-	OpponentDivision int     `json:"opponentDivision"`
-	Games            int     `json:"games"`
-	NonDrawGames     int     `json:"nonDrawGames"`
-	Wins             int     `json:"wins"`
-	Losses           int     `json:"losses"`
-	Draws            int     `json:"draws"`
-	WinRate          float64 `json:"winRate"`
+	OpponentDivision    int     `json:"opponentDivision"`
+	Games               int     `json:"games"`
+	NonDrawGames        int     `json:"nonDrawGames"`
+	Wins                int     `json:"wins"`
+	Losses              int     `json:"losses"`
+	Draws               int     `json:"draws"`
+	WinRate             float64 `json:"winRate"`
+	WinRateLow          float64 `json:"winRateLow"`
+	WinRateHigh         float64 `json:"winRateHigh"`
+	DrawAdjustedWinRate float64 `json:"drawAdjustedWinRate"`
+	Confidence          string  `json:"confidence"`
 }
 
 type DivisionWinrateRow struct {
-	Division     int     `json:"division"`
-	Games        int     `json:"games"`
-	NonDrawGames int     `json:"nonDrawGames"`
-	Wins         int     `json:"wins"`
-	Losses       int     `json:"losses"`
-	Draws        int     `json:"draws"`
-	WinRate      float64 `json:"winRate"`
+	Division            int     `json:"division"`
+	Games               int     `json:"games"`
+	NonDrawGames        int     `json:"nonDrawGames"`
+	Wins                int     `json:"wins"`
+	Losses              int     `json:"losses"`
+	Draws               int     `json:"draws"`
+	WinRate             float64 `json:"winRate"`
+	WinRateLow          float64 `json:"winRateLow"`
+	WinRateHigh         float64 `json:"winRateHigh"`
+	DrawAdjustedWinRate float64 `json:"drawAdjustedWinRate"`
+	Confidence          string  `json:"confidence"`
 }
 
 type DivisionVsWinrateRow struct {
-	Division         int     `json:"division"`
-	OpponentDivision int     `json:"opponentDivision"`
-	Games            int     `json:"games"`
-	NonDrawGames     int     `json:"nonDrawGames"`
-	Wins             int     `json:"wins"`
-	Losses           int     `json:"losses"`
-	Draws            int     `json:"draws"`
-	WinRate          float64 `json:"winRate"`
+	Division            int     `json:"division"`
+	OpponentDivision    int     `json:"opponentDivision"`
+	Games               int     `json:"games"`
+	NonDrawGames        int     `json:"nonDrawGames"`
+	Wins                int     `json:"wins"`
+	Losses              int     `json:"losses"`
+	Draws               int     `json:"draws"`
+	WinRate             float64 `json:"winRate"`
+	WinRateLow          float64 `json:"winRateLow"`
+	WinRateHigh         float64 `json:"winRateHigh"`
+	DrawAdjustedWinRate float64 `json:"drawAdjustedWinRate"`
+	Confidence          string  `json:"confidence"`
 }
 
 type DivisionOnMapWinrateRow struct {
-	Division     int     `json:"division"`
-	Map          string  `json:"map"`
-	Games        int     `json:"games"`
-	NonDrawGames int     `json:"nonDrawGames"`
-	Wins         int     `json:"wins"`
-	Losses       int     `json:"losses"`
-	Draws        int     `json:"draws"`
-	WinRate      float64 `json:"winRate"`
+	Division            int     `json:"division"`
+	Map                 string  `json:"map"`
+	Games               int     `json:"games"`
+	NonDrawGames        int     `json:"nonDrawGames"`
+	Wins                int     `json:"wins"`
+	Losses              int     `json:"losses"`
+	Draws               int     `json:"draws"`
+	WinRate             float64 `json:"winRate"`
+	WinRateLow          float64 `json:"winRateLow"`
+	WinRateHigh         float64 `json:"winRateHigh"`
+	DrawAdjustedWinRate float64 `json:"drawAdjustedWinRate"`
+	Confidence          string  `json:"confidence"`
 }
 
 type DivisionOnMapVsWinrateRow struct {
-	Division         int     `json:"division"`
-	OpponentDivision int     `json:"opponentDivision"`
-	Map              string  `json:"map"`
-	Games            int     `json:"games"`
-	NonDrawGames     int     `json:"nonDrawGames"`
-	Wins             int     `json:"wins"`
-	Losses           int     `json:"losses"`
-	Draws            int     `json:"draws"`
-	WinRate          float64 `json:"winRate"`
+	Division            int     `json:"division"`
+	OpponentDivision    int     `json:"opponentDivision"`
+	Map                 string  `json:"map"`
+	Games               int     `json:"games"`
+	NonDrawGames        int     `json:"nonDrawGames"`
+	Wins                int     `json:"wins"`
+	Losses              int     `json:"losses"`
+	Draws               int     `json:"draws"`
+	WinRate             float64 `json:"winRate"`
+	WinRateLow          float64 `json:"winRateLow"`
+	WinRateHigh         float64 `json:"winRateHigh"`
+	DrawAdjustedWinRate float64 `json:"drawAdjustedWinRate"`
+	Confidence          string  `json:"confidence"`
 }
 
 func makeRequest(method, url string, body []byte, headers map[string]string) (*http.Response, error) {
@@ -285,6 +312,9 @@ func (a *App) SendRankedReplaysToAPI(replays []RankedReplayInput) map[string]any
 		return map[string]any{"success": false, "status": resp.StatusCode, "body": bodyText}
 	}
 
+	// Newly stored replays change the analytics, so cached responses are stale.
+	a.ClearRankedReplaysAnalyticsCache()
+
 	var out any
 	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
 		return map[string]any{"success": true}
@@ -298,45 +328,34 @@ func (a *App) SendRankedReplaysToAPI(replays []RankedReplayInput) map[string]any
 	return map[string]any{"success": true, "data": out}
 }
 
+// GetPlayerReplays returns the replay records of a player. Only records newer
+// than the last sync are downloaded; the rest come from the local store.
 func (a *App) GetPlayerReplays(id string) []GetReplay {
-	if apiUrl == "" || apiKey == "" {
-		log.Println("Error: API_URL or API_KEY is not set")
-		return []GetReplay{}
-	}
-
-	query := fmt.Sprintf("%s/players/%s/replays", apiUrl, id)
-
-	headers := map[string]string{
-		"Authorization": "Bearer " + apiKey,
-	}
-
-	resp, err := makeRequest("GET", query, nil, headers)
+	replays, err := syncPlayerReplays(id)
 	if err != nil {
-		log.Println(err)
-		return []GetReplay{}
+		log.Printf("Error syncing replays for %s: %v", id, err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Error: received non-OK HTTP status: %d", resp.StatusCode)
+	if replays == nil {
 		return []GetReplay{}
 	}
 
-	var result []GetReplay
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Printf("Error decoding response body: %v", err)
-		return nil
-	}
-
-	return result
+	return replays
 }
 
 // GetRankedReplaysAnalytics fetches global ranked-replay analytics.
 // Filters:
-// - maxRank: when >0, limits to players with rank <= maxRank (e.g. 100 => top 100)
-// - minElo:  when >0, limits to players with elo >= minElo
+// - maxRank:     when >0, limits to players with rank <= maxRank (e.g. 100 => top 100)
+// - minElo:      when >0, limits to players with elo >= minElo
+// - from/to:     limits to games played inside the time window
+// - versions:    limits to the given game versions (patches)
+// - maps:        limits to the given map keys
+// - divisions:   limits to games where one side played one of the divisions
+// - minDuration: when >0, skips games shorter than minDuration seconds
+// - sortBy:      "games", "winRate" or "winRateLow" orders every row list
 // Note: filter semantics depend on the remote API implementation.
-func (a *App) GetRankedReplaysAnalytics(maxRank int, minElo int) RankedReplaysAnalyticsResponse {
+// Responses are cached for rankedAnalyticsCacheTTL per distinct query.
+func (a *App) GetRankedReplaysAnalytics(filter RankedReplaysAnalyticsFilter) RankedReplaysAnalyticsResponse {
 	if apiUrl == "" || apiKey == "" {
 		log.Println("Error: API_URL or API_KEY is not set")
 		return RankedReplaysAnalyticsResponse{}
@@ -354,15 +373,14 @@ func (a *App) GetRankedReplaysAnalytics(maxRank int, minElo int) RankedReplaysAn
 	}
 
 	q := parsed.Query()
-	if maxRank > 0 {
-		q.Set("maxRank", strconv.Itoa(maxRank))
-	}
-	if minElo > 0 {
-		q.Set("minElo", strconv.Itoa(minElo))
-	}
+	filter.encode(q)
 	parsed.RawQuery = q.Encode()
 	finalURL := parsed.String()
 
+	if cached, ok := getCachedRankedAnalytics(finalURL); ok {
+		return sortedRankedAnalytics(cached, filter.SortBy)
+	}
+
 	resp, err := makeRequest("GET", finalURL, nil, headers)
 	if err != nil {
 		log.Printf("GetRankedReplaysAnalytics request failed url=%s err=%v", finalURL, err)
@@ -392,12 +410,14 @@ func (a *App) GetRankedReplaysAnalytics(maxRank int, minElo int) RankedReplaysAn
 	}
 
 	log.Printf(
-		"GetRankedReplaysAnalytics ok maxRank=%d minElo=%d totalGames=%d uniqueSubmitters=%d",
-		maxRank,
-		minElo,
+		"GetRankedReplaysAnalytics ok query=%s totalGames=%d uniqueSubmitters=%d",
+		parsed.RawQuery,
 		result.TotalGames,
 		result.UniqueSubmitters,
 	)
 
-	return result
+	annotateRankedAnalytics(&result)
+	storeCachedRankedAnalytics(finalURL, result)
+
+	return sortedRankedAnalytics(result, filter.SortBy)
 }
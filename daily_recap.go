@@ -2,10 +2,10 @@ package main
 
 import (
 	"fmt"
-	"io"
-	"net/http"
-	"strconv"
+	"log"
+	"math"
 	"time"
+	_ "time/tzdata"
 )
 
 type DailyRecap struct {
@@ -13,69 +13,163 @@ type DailyRecap struct {
 	GamesPlayed int `json:"gamesPlayed"`
 	Wins        int `json:"wins"`
 	Losses      int `json:"losses"`
+	Draws       int `json:"draws"`
 }
 
-func (a *App) GetDailyRecap(playerId string) DailyRecap {
-	return DailyRecap{} // TODO: Remove when API is available again
+// recapDay returns the bounds of the day containing now, using the day start
+// and time zone from the settings.
+func recapDay(settings Settings, now time.Time) (time.Time, time.Time, error) {
+	loc := time.Local
+	if settings.TimeZone != "" {
+		var err error
+		loc, err = time.LoadLocation(settings.TimeZone)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid time zone %q: %w", settings.TimeZone, err)
+		}
+	}
+
+	var dayStart time.Time
+	if settings.DayStartsAt != "" {
+		var err error
+		dayStart, err = time.Parse("15:04", settings.DayStartsAt)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid day start %q: %w", settings.DayStartsAt, err)
+		}
+	}
+
+	// The day starts at the same wall-clock time on DST changes too, so the
+	// bounds are built from the clock rather than offset from midnight.
+	now = now.In(loc)
+	start := time.Date(now.Year(), now.Month(), now.Day(), dayStart.Hour(), dayStart.Minute(), 0, 0, loc)
+	if start.After(now) {
+		start = start.AddDate(0, 0, -1)
+	}
+
+	return start, start.AddDate(0, 0, 1), nil
+}
+
+// latestEugenElo returns the Elo of the player's most recent stored stats
+// snapshot, or 0 when there is none.
+func latestEugenElo(playerId string) float64 {
+	eugenSnapshotsMu.Lock()
+	snapshots, err := loadEugenSnapshots(playerId)
+	eugenSnapshotsMu.Unlock()
+	if err != nil || len(snapshots) == 0 {
+		return 0
+	}
+	return snapshots[len(snapshots)-1].Profile.Elo
+}
 
-	url := fmt.Sprintf("https://api.eugnet.com/gamehistory/list?userid=%v&page=0", playerId)
-	fmt.Printf("Fetching URL: %s\n", url)
-	resp, err := http.Get(url)
+// eugenSnapshotAt returns the snapshot known to describe the player at t, that
+// is one fetched before t and still current at or after it.
+func eugenSnapshotAt(playerId string, t time.Time) (EugenPlayerSnapshot, bool) {
+	eugenSnapshotsMu.Lock()
+	snapshots, err := loadEugenSnapshots(playerId)
+	eugenSnapshotsMu.Unlock()
 	if err != nil {
-		fmt.Printf("Error fetching eugnet api: %v\n", err)
-		return DailyRecap{}
+		return EugenPlayerSnapshot{}, false
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Received non-OK HTTP status: %s\n", resp.Status)
-		return DailyRecap{}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		s := snapshots[i]
+		if !s.FetchedAt.After(t) {
+			return s, !s.LastSeenAt.Before(t)
+		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	return EugenPlayerSnapshot{}, false
+}
+
+// remoteDailyRecap counts the player's games stored by the remote API between
+// start and end. The API does not keep results, so only GamesPlayed is set.
+func remoteDailyRecap(playerId string, start, end time.Time) DailyRecap {
+	var recap DailyRecap
+
+	games, err := remoteReplayHistoryProvider{}.FetchGameHistory(playerId, 0)
 	if err != nil {
-		fmt.Printf("Error reading response body: %v\n", err)
-		return DailyRecap{}
+		log.Printf("Error fetching remote replays for %s: %v", playerId, err)
+	}
+	for _, g := range games {
+		playedAt := parseGameTime(g.Date)
+		if !playedAt.Before(start) && playedAt.Before(end) {
+			recap.GamesPlayed++
+		}
 	}
 
-	entries := extractGameEntries(string(body), 50)
-	fmt.Printf("Extracted %d entries\n", len(entries))
+	return recap
+}
 
-	if len(entries) == 0 {
-		fmt.Println("DailyRecap more entries found, stopping.")
+// GetDailyRecap summarises the games the player played today. Local replays
+// are used first; when there are none, the ranked counters of the Eugen stats
+// are compared with a snapshot taken at the start of the day, and without
+// such a snapshot the games the remote API stores are counted. An empty
+// playerId falls back to the DailyRecapUser setting.
+func (a *App) GetDailyRecap(playerId string) DailyRecap {
+	settings, err := a.GetSettings()
+	if err != nil {
+		log.Printf("Error loading settings: %v", err)
+	}
+	if playerId == "" {
+		playerId = settings.DailyRecapUser
+	}
+	if playerId == "" {
 		return DailyRecap{}
 	}
 
-	const layoutISO = "2006-01-02"
+	start, end, err := recapDay(settings, time.Now())
+	if err != nil {
+		log.Printf("Error: %v, using local midnight", err)
+		start, end, _ = recapDay(Settings{}, time.Now())
+	}
 
-	today := time.Now().Format(layoutISO)
+	current, err := a.GetEugenPlayer(playerId)
+	if err != nil {
+		log.Printf("Error fetching Eugen stats for %s: %v", playerId, err)
+	}
+	var currentElo float64
+	if current != nil {
+		currentElo = current.Elo
+	}
 
-	var summary DailyRecap
-	for _, entry := range entries {
-		entryDate, err := time.Parse(layoutISO, entry.Date[:10])
-		if err != nil {
-			fmt.Printf("Error parsing entry date: %v\n", err)
+	var recap DailyRecap
+	records := buildPlayerGameRecords(getReplays(a.replayDirectories()), playerId, currentElo)
+	for _, r := range records {
+		if r.playedAt.Before(start) || !r.playedAt.Before(end) {
 			continue
 		}
 
-		if entryDate.Format(layoutISO) != today {
-			break
+		recap.GamesPlayed++
+		switch {
+		case r.outcome > 0:
+			recap.Wins++
+		case r.outcome < 0:
+			recap.Losses++
+		default:
+			recap.Draws++
 		}
-
-		summary.GamesPlayed++
-		if entry.Result == "victory" {
-			summary.Wins++
-		} else {
-			summary.Losses++
+		if r.eloBefore > 0 && r.eloAfter > 0 {
+			recap.EloChange += int(math.Round(r.eloAfter - r.eloBefore))
 		}
+	}
 
-		score, err := strconv.ParseFloat(entry.Score, 64)
-		if err != nil {
-			fmt.Printf("Error converting score to float: %v\n", err)
-			continue
-		}
-		summary.EloChange += int(score)
+	if recap.GamesPlayed > 0 {
+		return recap
+	}
+
+	var baseline EugenPlayerSnapshot
+	exact := false
+	if current != nil {
+		baseline, exact = eugenSnapshotAt(playerId, start)
 	}
+	if !exact {
+		return remoteDailyRecap(playerId, start, end)
+	}
+
+	prev := baseline.Profile.Ranked
+	recap.Wins = current.Ranked.Wins - prev.Wins
+	recap.Losses = current.Ranked.Losses - prev.Losses + current.Ranked.Fouls - prev.Fouls
+	recap.GamesPlayed = recap.Wins + recap.Losses
+	recap.EloChange = int(math.Round(current.Elo - baseline.Profile.Elo))
 
-	return summary
+	return recap
 }
@@ -0,0 +1,70 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+)
+
+// WARNO deck codes are base64 bit strings. The division ID follows the fixed
+// "DCR" header, as read by the deck decoder the frontend uses.
+const (
+	deckDivisionOffset = 18
+	deckDivisionBits   = 10
+)
+
+//go:embed frontend/src/data/divisions.json
+var divisionsJSON []byte
+
+var (
+	divisionNamesOnce sync.Once
+	divisionNames     map[int]string
+)
+
+func knownDivisions() map[int]string {
+	divisionNamesOnce.Do(func() {
+		var divisions []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(divisionsJSON, &divisions); err != nil {
+			log.Printf("Error reading divisions: %v", err)
+		}
+		divisionNames = make(map[int]string, len(divisions))
+		for _, d := range divisions {
+			divisionNames[d.ID] = d.Name
+		}
+	})
+	return divisionNames
+}
+
+// deckDivisionId returns the division of a deck code, or 0 when the code
+// cannot be read or names a division the app does not know.
+func deckDivisionId(code string) int {
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(code), "="))
+	if err != nil || len(data)*8 < deckDivisionOffset+deckDivisionBits {
+		return 0
+	}
+
+	id := 0
+	for i := deckDivisionOffset; i < deckDivisionOffset+deckDivisionBits; i++ {
+		bit := data[i/8] >> (7 - i%8) & 1
+		id = id<<1 | int(bit)
+	}
+
+	if _, ok := knownDivisions()[id]; !ok {
+		return 0
+	}
+	return id
+}
+
+// divisionName returns the name of a division ID, or "Unknown".
+func divisionName(id int) string {
+	if name, ok := knownDivisions()[id]; ok {
+		return name
+	}
+	return "Unknown"
+}
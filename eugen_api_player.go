@@ -4,76 +4,195 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
-type EugenPlayer struct {
-	ID                     string `json:"_id"`
-	Rev                    string `json:"_rev"`
-	TimeChallengePlayed    string `json:"@time_challenge_played"`
-	NbDcaBought            string `json:"@nb_dca_bought"`
-	NbArtBought            string `json:"@nb_art_bought"`
-	RankedNation0          string `json:"ranked_nation_0"`
-	CampaignNato           string `json:"@campaign_nato"`
-	CampaignWins           string `json:"@campaign_wins"`
-	CampaignLastGame       string `json:"@campaign_last_game"`
-	TimeCampaignPlayed     string `json:"@time_campaign_played"`
-	MultiPact              string `json:"@multi_pact"`
-	MultiLoss              string `json:"@multi_loss"`
-	XpSkirmish             string `json:"@xp_skirmish"`
-	DeploymentBreakthrough string `json:"@deployment_breakthrough"`
-	NbTankBought           string `json:"@nb_tank_bought"`
-	TimeTutorialPlayed     string `json:"@time_tutorial_played"`
-	NbRecoBought           string `json:"@nb_reco_bought"`
-	ELO                    string `json:"ELO"`
-	RankedWin              string `json:"ranked_win"`
-	RankedNation1          string `json:"ranked_nation_1"`
-	RankedLastGame         string `json:"ranked_last_game"`
-	ELOLBDeltaValue        string `json:"ELO_LB_delta_value"`
-	ELOLBDeltaRank         string `json:"ELO_LB_delta_rank"`
-	ELOLBValue             string `json:"ELO_LB_value"`
-	RankedLoss             string `json:"ranked_loss"`
-	ELOLBRank              string `json:"ELO_LB_rank"`
-	SkirmishPlayed         string `json:"@skirmish_played"`
-	DeploymentCloseQuarter string `json:"@deployment_closequarter_conquest"`
-	MultiDraw              string `json:"@multi_draw"`
-	SkirmishWin            string `json:"@skirmish_win"`
-	SkirmishLoss           string `json:"@skirmish_loss"`
-	SkirmishLastGame       string `json:"@skirmish_last_game"`
-	TimeArmoryPlayed       string `json:"@time_armory_played"`
-	DeploymentConquest     string `json:"@deployment_conquest"`
-	SkirmishNato           string `json:"@skirmish_nato"`
-	Level                  string `json:"@level"`
-	TotalUnitBought        string `json:"@total_unit_bought"`
-	SkirmishWinAi6         string `json:"@skirmish_win_ai_6"`
-	XpRanked               string `json:"@xp_ranked"`
-	MultiWin               string `json:"@multi_win"`
-	TimeSkirmishPlayed     string `json:"@time_skirmish_played"`
-	SkirmishWinAi5         string `json:"@skirmish_win_ai_5"`
-	SkirmishWinAi4         string `json:"@skirmish_win_ai_4"`
-	SkirmishWinAi3         string `json:"@skirmish_win_ai_3"`
-	SkirmishWinAi2         string `json:"@skirmish_win_ai_2"`
-	SkirmishDraw           string `json:"@skirmish_draw"`
-	XpMulti                string `json:"@xp_multi"`
-	NbAirBought            string `json:"@nb_air_bought"`
-	NbSupBought            string `json:"@nb_sup_bought"`
-	SkirmishPact           string `json:"@skirmish_pact"`
-	MultiLastGame          string `json:"@multi_last_game"`
-	TimeRankedPlayed       string `json:"@time_ranked_played"`
-	TimeMenuPlayed         string `json:"@time_menu_played"`
-	RankedFoul             string `json:"ranked_foul"`
-	DeploymentStrategic    string `json:"@deployment_strategic"`
-	MultiPlayed            string `json:"@multi_played"`
-	TimeMultiPlayed        string `json:"@time_multi_played"`
-	MultiNato              string `json:"@multi_nato"`
-	NbInfBought            string `json:"@nb_inf_bought"`
-	TimeReplayPlayed       string `json:"@time_replay_played"`
-	NbAtBought             string `json:"@nb_at_bought"`
-	XpCampaign             string `json:"@xp_campaign"`
-	TimeStrategicPlayed    string `json:"@time_strategic_played"`
+// eugenStatsDocument is the raw Eugen stats document. Values are usually
+// numbers encoded as strings; missing or malformed fields read as zero.
+type eugenStatsDocument map[string]json.RawMessage
+
+func (d eugenStatsDocument) string(key string) string {
+	raw, ok := d[key]
+	if !ok {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	return strings.TrimSpace(string(raw))
+}
+
+func (d eugenStatsDocument) float(key string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(d.string(key)), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func (d eugenStatsDocument) int(key string) int {
+	return int(d.float(key))
+}
+
+type EugenModeRecord struct {
+	Played  int     `json:"played"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	Draws   int     `json:"draws"`
+	Fouls   int     `json:"fouls"`
+	WinRate float64 `json:"winRate"`
+}
+
+type EugenUnitPurchase struct {
+	Category string  `json:"category"`
+	Count    int     `json:"count"`
+	Percent  float64 `json:"percent"`
+}
+
+type EugenModeTime struct {
+	Mode    string `json:"mode"`
+	Seconds int64  `json:"seconds"`
+}
+
+// EugenPlayerProfile is the typed view of a player's Eugen stats document.
+// Winrates and NatoShare are fractions in 0..1, unit purchase shares are
+// percentages.
+type EugenPlayerProfile struct {
+	ID    string `json:"id"`
+	Level int    `json:"level"`
+
+	Elo                  float64 `json:"elo"`
+	LeaderboardElo       float64 `json:"leaderboardElo"`
+	LeaderboardEloDelta  float64 `json:"leaderboardEloDelta"`
+	LeaderboardRank      int     `json:"leaderboardRank"`
+	LeaderboardRankDelta int     `json:"leaderboardRankDelta"`
+
+	Ranked       EugenModeRecord `json:"ranked"`
+	Multi        EugenModeRecord `json:"multi"`
+	Skirmish     EugenModeRecord `json:"skirmish"`
+	CampaignWins int             `json:"campaignWins"`
+
+	XpRanked   int `json:"xpRanked"`
+	XpMulti    int `json:"xpMulti"`
+	XpSkirmish int `json:"xpSkirmish"`
+	XpCampaign int `json:"xpCampaign"`
+
+	TotalUnitsBought int                 `json:"totalUnitsBought"`
+	UnitPurchases    []EugenUnitPurchase `json:"unitPurchases"`
+
+	TimePlayed             []EugenModeTime `json:"timePlayed"`
+	TotalTimePlayedSeconds int64           `json:"totalTimePlayedSeconds"`
+
+	NatoGames     int     `json:"natoGames"`
+	PactGames     int     `json:"pactGames"`
+	NatoShare     float64 `json:"natoShare"`
+	PreferredSide string  `json:"preferredSide"`
 }
 
-func (a *App) GetEugenPlayer(playerId string) (*EugenPlayer, error) {
+// eugenUnitCategories maps unit purchase categories to their document keys.
+var eugenUnitCategories = []struct {
+	category string
+	key      string
+}{
+	{"tank", "@nb_tank_bought"},
+	{"inf", "@nb_inf_bought"},
+	{"air", "@nb_air_bought"},
+	{"art", "@nb_art_bought"},
+	{"reco", "@nb_reco_bought"},
+	{"aa", "@nb_dca_bought"},
+	{"at", "@nb_at_bought"},
+	{"sup", "@nb_sup_bought"},
+}
+
+var eugenPlayModes = []string{
+	"ranked", "multi", "skirmish", "campaign", "strategic",
+	"challenge", "armory", "tutorial", "replay", "menu",
+}
+
+func winRate(wins, games int) float64 {
+	if games <= 0 {
+		return 0
+	}
+	return float64(wins) / float64(games)
+}
+
+func newEugenPlayerProfile(doc eugenStatsDocument) EugenPlayerProfile {
+	p := EugenPlayerProfile{
+		ID:                   doc.string("_id"),
+		Level:                doc.int("@level"),
+		Elo:                  doc.float("ELO"),
+		LeaderboardElo:       doc.float("ELO_LB_value"),
+		LeaderboardEloDelta:  doc.float("ELO_LB_delta_value"),
+		LeaderboardRank:      doc.int("ELO_LB_rank"),
+		LeaderboardRankDelta: doc.int("ELO_LB_delta_rank"),
+		CampaignWins:         doc.int("@campaign_wins"),
+		XpRanked:             doc.int("@xp_ranked"),
+		XpMulti:              doc.int("@xp_multi"),
+		XpSkirmish:           doc.int("@xp_skirmish"),
+		XpCampaign:           doc.int("@xp_campaign"),
+		TotalUnitsBought:     doc.int("@total_unit_bought"),
+	}
+
+	// Ranked has no played counter; fouls count as games, as in the client.
+	p.Ranked = EugenModeRecord{
+		Wins:   doc.int("ranked_win"),
+		Losses: doc.int("ranked_loss"),
+		Fouls:  doc.int("ranked_foul"),
+	}
+	p.Ranked.Played = p.Ranked.Wins + p.Ranked.Losses + p.Ranked.Fouls
+	p.Ranked.WinRate = winRate(p.Ranked.Wins, p.Ranked.Played)
+
+	for _, mode := range []struct {
+		record *EugenModeRecord
+		prefix string
+	}{{&p.Multi, "@multi_"}, {&p.Skirmish, "@skirmish_"}} {
+		mode.record.Played = doc.int(mode.prefix + "played")
+		mode.record.Wins = doc.int(mode.prefix + "win")
+		mode.record.Losses = doc.int(mode.prefix + "loss")
+		mode.record.Draws = doc.int(mode.prefix + "draw")
+		mode.record.WinRate = winRate(mode.record.Wins, mode.record.Played)
+	}
+
+	var bought int
+	for _, c := range eugenUnitCategories {
+		count := doc.int(c.key)
+		bought += count
+		p.UnitPurchases = append(p.UnitPurchases, EugenUnitPurchase{Category: c.category, Count: count})
+	}
+	if bought > 0 {
+		for i := range p.UnitPurchases {
+			p.UnitPurchases[i].Percent = float64(p.UnitPurchases[i].Count) * 100 / float64(bought)
+		}
+	}
+
+	for _, mode := range eugenPlayModes {
+		seconds := int64(doc.float("@time_" + mode + "_played"))
+		p.TotalTimePlayedSeconds += seconds
+		p.TimePlayed = append(p.TimePlayed, EugenModeTime{Mode: mode, Seconds: seconds})
+	}
+
+	p.NatoGames = doc.int("ranked_nation_0") + doc.int("@multi_nato") + doc.int("@skirmish_nato")
+	p.PactGames = doc.int("ranked_nation_1") + doc.int("@multi_pact") + doc.int("@skirmish_pact")
+	if total := p.NatoGames + p.PactGames; total > 0 {
+		p.NatoShare = float64(p.NatoGames) / float64(total)
+		switch {
+		case p.NatoGames > p.PactGames:
+			p.PreferredSide = "NATO"
+		case p.PactGames > p.NatoGames:
+			p.PreferredSide = "PACT"
+		}
+	}
+
+	return p
+}
+
+func fetchEugenStatsDocument(playerId string) (eugenStatsDocument, error) {
 	url := fmt.Sprintf(eugenApiUrl+"/stats/u29_%s", playerId)
 
 	resp, err := http.Get(url)
@@ -91,10 +210,25 @@ func (a *App) GetEugenPlayer(playerId string) (*EugenPlayer, error) {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var player EugenPlayer
-	if err := json.Unmarshal(body, &player); err != nil {
+	var doc eugenStatsDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
 
-	return &player, nil
+	return doc, nil
+}
+
+func (a *App) GetEugenPlayer(playerId string) (*EugenPlayerProfile, error) {
+	doc, err := fetchEugenStatsDocument(playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := newEugenPlayerProfile(doc)
+
+	if err := recordEugenSnapshot(playerId, profile); err != nil {
+		log.Printf("Error storing Eugen snapshot for %s: %v", playerId, err)
+	}
+
+	return &profile, nil
 }
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// eugenSnapshotLimit caps the stored snapshots per player.
+const eugenSnapshotLimit = 200
+
+// EugenPlayerSnapshot is one observed state of a player's stats document.
+// Identical fetches only move LastSeenAt forward.
+type EugenPlayerSnapshot struct {
+	FetchedAt  time.Time          `json:"fetchedAt"`
+	LastSeenAt time.Time          `json:"lastSeenAt"`
+	Profile    EugenPlayerProfile `json:"profile"`
+}
+
+type EugenUnitMixChange struct {
+	Category       string  `json:"category"`
+	Bought         int     `json:"bought"`
+	RecentPercent  float64 `json:"recentPercent"`
+	OverallPercent float64 `json:"overallPercent"`
+	Change         float64 `json:"change"`
+}
+
+// EugenPlayerActivity is what changed between two snapshots of a player. Since
+// is the last time the older state was seen, Until the first time the newer
+// one was, so the games happened in between. A positive RankChange means the
+// player climbed.
+type EugenPlayerActivity struct {
+	PlayerId      string               `json:"playerId"`
+	Since         time.Time            `json:"since"`
+	Until         time.Time            `json:"until"`
+	GamesPlayed   int                  `json:"gamesPlayed"`
+	RankedGames   int                  `json:"rankedGames"`
+	RankedWins    int                  `json:"rankedWins"`
+	RankedLosses  int                  `json:"rankedLosses"`
+	RankedFouls   int                  `json:"rankedFouls"`
+	EloChange     float64              `json:"eloChange"`
+	RankChange    int                  `json:"rankChange"`
+	UnitsBought   int                  `json:"unitsBought"`
+	UnitMixChange []EugenUnitMixChange `json:"unitMixChange"`
+}
+
+var eugenSnapshotsMu sync.Mutex
+
+func getEugenSnapshotsFilePath(playerId string) (string, error) {
+	dir, err := getLocalAppDataDir("warno-replays-analyser", "eugenSnapshots")
+	if err != nil {
+		return "", fmt.Errorf("getting eugenSnapshots directory: %w", err)
+	}
+	return filepath.Join(dir, sanitizeFileName(playerId)+".json"), nil
+}
+
+func loadEugenSnapshots(playerId string) ([]EugenPlayerSnapshot, error) {
+	filePath, err := getEugenSnapshotsFilePath(playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading snapshots: %w", err)
+	}
+
+	var snapshots []EugenPlayerSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, fmt.Errorf("unmarshaling snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+func saveEugenSnapshots(playerId string, snapshots []EugenPlayerSnapshot) error {
+	filePath, err := getEugenSnapshotsFilePath(playerId)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return fmt.Errorf("marshaling snapshots: %w", err)
+	}
+
+	return writeFileAtomic(filePath, data, 0644)
+}
+
+// recordEugenSnapshot appends profile to the player's snapshots, or refreshes
+// LastSeenAt when nothing changed since the latest one.
+func recordEugenSnapshot(playerId string, profile EugenPlayerProfile) error {
+	eugenSnapshotsMu.Lock()
+	defer eugenSnapshotsMu.Unlock()
+
+	// Saving over a file that cannot be read would lose the whole history.
+	snapshots, err := loadEugenSnapshots(playerId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if n := len(snapshots); n > 0 && reflect.DeepEqual(snapshots[n-1].Profile, profile) {
+		snapshots[n-1].LastSeenAt = now
+	} else {
+		snapshots = append(snapshots, EugenPlayerSnapshot{FetchedAt: now, LastSeenAt: now, Profile: profile})
+	}
+
+	if len(snapshots) > eugenSnapshotLimit {
+		snapshots = snapshots[len(snapshots)-eugenSnapshotLimit:]
+	}
+
+	return saveEugenSnapshots(playerId, snapshots)
+}
+
+func diffEugenSnapshots(playerId string, from, to EugenPlayerSnapshot) EugenPlayerActivity {
+	prev, cur := from.Profile, to.Profile
+
+	activity := EugenPlayerActivity{
+		PlayerId:     playerId,
+		Since:        from.LastSeenAt,
+		Until:        to.FetchedAt,
+		RankedGames:  cur.Ranked.Played - prev.Ranked.Played,
+		RankedWins:   cur.Ranked.Wins - prev.Ranked.Wins,
+		RankedLosses: cur.Ranked.Losses - prev.Ranked.Losses,
+		RankedFouls:  cur.Ranked.Fouls - prev.Ranked.Fouls,
+		EloChange:    cur.Elo - prev.Elo,
+		UnitsBought:  cur.TotalUnitsBought - prev.TotalUnitsBought,
+	}
+	activity.GamesPlayed = activity.RankedGames +
+		(cur.Multi.Played - prev.Multi.Played) +
+		(cur.Skirmish.Played - prev.Skirmish.Played)
+
+	if prev.LeaderboardRank > 0 && cur.LeaderboardRank > 0 {
+		activity.RankChange = prev.LeaderboardRank - cur.LeaderboardRank
+	}
+
+	previous := make(map[string]EugenUnitPurchase, len(prev.UnitPurchases))
+	for _, p := range prev.UnitPurchases {
+		previous[p.Category] = p
+	}
+
+	var recentTotal int
+	for _, p := range cur.UnitPurchases {
+		bought := p.Count - previous[p.Category].Count
+		if bought > 0 {
+			recentTotal += bought
+		}
+		activity.UnitMixChange = append(activity.UnitMixChange, EugenUnitMixChange{
+			Category:       p.Category,
+			Bought:         bought,
+			OverallPercent: previous[p.Category].Percent,
+		})
+	}
+	for i := range activity.UnitMixChange {
+		change := &activity.UnitMixChange[i]
+		if recentTotal > 0 && change.Bought > 0 {
+			change.RecentPercent = float64(change.Bought) * 100 / float64(recentTotal)
+		}
+		if recentTotal > 0 {
+			change.Change = change.RecentPercent - change.OverallPercent
+		}
+	}
+
+	return activity
+}
+
+// GetEugenPlayerActivity fetches the player's current stats and returns what
+// changed since the latest stored snapshot. When the stats cannot be fetched,
+// or nothing was stored before, the activity is empty.
+func (a *App) GetEugenPlayerActivity(playerId string) (EugenPlayerActivity, error) {
+	eugenSnapshotsMu.Lock()
+	snapshots, err := loadEugenSnapshots(playerId)
+	eugenSnapshotsMu.Unlock()
+	if err != nil {
+		return EugenPlayerActivity{}, err
+	}
+
+	profile, err := a.GetEugenPlayer(playerId)
+	if err != nil {
+		if len(snapshots) == 0 {
+			return EugenPlayerActivity{}, fmt.Errorf("no stats stored for player %s: %w", playerId, err)
+		}
+		log.Printf("Error refreshing Eugen stats for %s, reporting no change: %v", playerId, err)
+		latest := snapshots[len(snapshots)-1]
+		return EugenPlayerActivity{PlayerId: playerId, Since: latest.LastSeenAt, Until: latest.LastSeenAt}, nil
+	}
+
+	now := time.Now().UTC()
+	if len(snapshots) == 0 {
+		return EugenPlayerActivity{PlayerId: playerId, Since: now, Until: now}, nil
+	}
+
+	live := EugenPlayerSnapshot{FetchedAt: now, LastSeenAt: now, Profile: *profile}
+	return diffEugenSnapshots(playerId, snapshots[len(snapshots)-1], live), nil
+}
+
+// GetFavoritePlayersActivity returns the activity of every favorite player,
+// most active first. Players whose stats cannot be loaded are skipped.
+func (a *App) GetFavoritePlayersActivity() []EugenPlayerActivity {
+	settings, err := a.GetSettings()
+	if err != nil {
+		log.Printf("Error loading settings: %v", err)
+		return []EugenPlayerActivity{}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	activities := []EugenPlayerActivity{}
+
+	for _, playerId := range settings.FavoritePlayerIds {
+		wg.Add(1)
+		go func(playerId string) {
+			defer wg.Done()
+
+			activity, err := a.GetEugenPlayerActivity(playerId)
+			if err != nil {
+				log.Printf("Error getting activity for %s: %v", playerId, err)
+				return
+			}
+
+			mu.Lock()
+			activities = append(activities, activity)
+			mu.Unlock()
+		}(playerId)
+	}
+	wg.Wait()
+
+	sort.Slice(activities, func(i, j int) bool {
+		if activities[i].GamesPlayed != activities[j].GamesPlayed {
+			return activities[i].GamesPlayed > activities[j].GamesPlayed
+		}
+		return activities[i].PlayerId < activities[j].PlayerId
+	})
+
+	return activities
+}
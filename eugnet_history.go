@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// eugnetDefaultLimit is the number of games fetched when no limit is given;
+// every game costs an extra request for its details.
+const eugnetDefaultLimit = 10
+
+var (
+	eugnetGameIDRe   = regexp.MustCompile(`gameid=([a-f0-9]{32})`)
+	eugnetScoreRe    = regexp.MustCompile(`\(\d+/\d+(?: (-?\d*\.\d+))?\)`)
+	eugnetTimeRe     = regexp.MustCompile(`myDate=new Date\((\d+\.\d+)\)`)
+	eugnetFuldaRe    = regexp.MustCompile(`fulda`)
+	eugnetNameRe     = regexp.MustCompile(`<span class="name">(.*?)</span>`)
+	eugnetEloCellsRe = regexp.MustCompile(`<td>([-+]?[\d]+)</td>`)
+)
+
+// eugnetHistoryProvider scrapes the eugnet game history pages. The public
+// site is gone, so it is only registered when eugnetApiUrl points to a mirror.
+type eugnetHistoryProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newEugnetHistoryProvider(baseURL string) *eugnetHistoryProvider {
+	return &eugnetHistoryProvider{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *eugnetHistoryProvider) Name() string { return "eugnet" }
+
+func (p *eugnetHistoryProvider) get(path string, query url.Values) (string, error) {
+	resp, err := p.client.Get(p.baseURL + path + "?" + query.Encode())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading response body: %w", err)
+	}
+
+	return string(body), nil
+}
+
+// FetchGameHistory reads the first page of the player's history, then fills
+// in names and Elo from each game's detail page. Games whose details cannot be
+// loaded are kept with what the list page had.
+func (p *eugnetHistoryProvider) FetchGameHistory(playerId string, limit int) ([]PlayerGame, error) {
+	if limit <= 0 {
+		limit = eugnetDefaultLimit
+	}
+
+	list, err := p.get("/gamehistory/list", url.Values{"userid": {playerId}, "page": {"0"}})
+	if err != nil {
+		return nil, fmt.Errorf("fetching game list: %w", err)
+	}
+
+	entries := extractGameEntries(list, limit)
+
+	for i := range entries {
+		details, err := p.get("/gamehistory/game", url.Values{"gameid": {entries[i].GameID}})
+		if err != nil {
+			log.Printf("Error fetching eugnet game %s: %v", entries[i].GameID, err)
+			continue
+		}
+
+		playerName, enemyName, playerElo, enemyElo, playerEloChange, enemyEloChange := extractPlayerAndEnemyData(details, entries[i].Result)
+		if playerName == "" || enemyName == "" {
+			continue
+		}
+
+		entries[i].Player = playerName
+		entries[i].Enemy = enemyName
+		entries[i].PlayerElo = []string{playerElo, addStrings(playerElo, playerEloChange)}
+		entries[i].EnemyElo = []string{enemyElo, addStrings(enemyElo, enemyEloChange)}
+		entries[i].PlayerEloChange = playerEloChange
+		entries[i].EnemyEloChange = enemyEloChange
+	}
+
+	return entries, nil
+}
+
+// extractGameEntries reads up to max games from a history list page. Games
+// without an Elo score are skipped.
+func extractGameEntries(data string, max int) []PlayerGame {
+	var entries []PlayerGame
+
+	fuldas := eugnetFuldaRe.FindAllStringSubmatch(data, -1)
+	gameIDs := eugnetGameIDRe.FindAllStringSubmatch(data, -1)
+	scores := eugnetScoreRe.FindAllStringSubmatch(data, -1)
+	times := eugnetTimeRe.FindAllStringSubmatch(data, -1)
+
+	for i := 0; i < len(gameIDs) && i < max; i++ {
+		if i >= len(scores) || scores[i][1] == "" {
+			continue
+		}
+
+		if i < len(fuldas) && len(fuldas[i]) > 1 && fuldas[i][1] == "" {
+			continue
+		}
+
+		score := scores[i][1]
+		dateStr := ""
+		if i < len(times) {
+			timestamp, err := parseTimestamp(times[i][1])
+			if err == nil {
+				dateStr = timestamp
+			} else {
+				log.Printf("Error parsing eugnet timestamp: %v", err)
+			}
+		}
+
+		result := "victory"
+		if score[0] == '-' {
+			result = "defeat"
+		}
+
+		entries = append(entries, PlayerGame{
+			GameID: gameIDs[i][1],
+			Score:  score,
+			Result: result,
+			Date:   dateStr,
+		})
+	}
+
+	return entries
+}
+
+// extractPlayerAndEnemyData reads both players of a game detail page. The
+// page does not say which side is the requested player, so the sign of the Elo
+// change is matched against the result from the list page.
+func extractPlayerAndEnemyData(data string, result string) (playerName, enemyName, playerElo, enemyElo, playerEloChange, enemyEloChange string) {
+	playerMatches := eugnetNameRe.FindAllStringSubmatch(data, -1)
+	eloChangeMatches := eugnetEloCellsRe.FindAllStringSubmatch(data, -1)
+
+	if len(playerMatches) < 2 || len(eloChangeMatches) < 4 {
+		return "", "", "", "", "", ""
+	}
+
+	player1Name := playerMatches[0][1]
+	player2Name := playerMatches[1][1]
+
+	player1Elo := eloChangeMatches[2][1]
+	player1EloChange := eloChangeMatches[3][1]
+	player2Elo := eloChangeMatches[0][1]
+	player2EloChange := eloChangeMatches[1][1]
+
+	if result == "victory" {
+		if player1EloChange[0] == '-' {
+			playerName, enemyName = player1Name, player2Name
+			playerElo, enemyElo = player2Elo, player1Elo
+			playerEloChange, enemyEloChange = player2EloChange, player1EloChange
+		} else {
+			playerName, enemyName = player2Name, player1Name
+			playerElo, enemyElo = player1Elo, player2Elo
+			playerEloChange, enemyEloChange = player1EloChange, player2EloChange
+		}
+	} else if result == "defeat" {
+		if player1EloChange[0] == '+' {
+			playerName, enemyName = player1Name, player2Name
+			playerElo, enemyElo = player2Elo, player1Elo
+			playerEloChange, enemyEloChange = player2EloChange, player1EloChange
+		} else {
+			playerName, enemyName = player2Name, player1Name
+			playerElo, enemyElo = player1Elo, player2Elo
+			playerEloChange, enemyEloChange = player1EloChange, player2EloChange
+		}
+	}
+
+	return playerName, enemyName, playerElo, enemyElo, playerEloChange, enemyEloChange
+}
+
+func parseTimestamp(timestampStr string) (string, error) {
+	timestamp, err := strconv.ParseFloat(timestampStr, 64)
+	if err != nil {
+		return "", err
+	}
+
+	t := time.Unix(int64(timestamp/1000), 0)
+	return t.Format(time.RFC3339), nil
+}
+
+func addStrings(base, delta string) string {
+	baseInt, err1 := strconv.Atoi(base)
+	deltaInt, err2 := strconv.Atoi(delta)
+	if err1 != nil || err2 != nil {
+		return base
+	}
+	return strconv.Itoa(baseInt + deltaInt)
+}
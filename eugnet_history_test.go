@@ -0,0 +1,173 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "eugnet", name))
+	if err != nil {
+		t.Fatalf("reading fixture %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func newEugnetFixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	list := readFixture(t, "list.html")
+	game := readFixture(t, "game.html")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gamehistory/list":
+			if r.URL.Query().Get("userid") != "123" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(list))
+		case "/gamehistory/game":
+			if r.URL.Query().Get("gameid") == "fedcba9876543210fedcba9876543210" {
+				http.Error(w, "gone", http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(game))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestExtractGameEntries(t *testing.T) {
+	entries := extractGameEntries(readFixture(t, "list.html"), 10)
+
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2 (unscored game skipped)", len(entries))
+	}
+
+	want := []struct {
+		id, score, result string
+		unix              int64
+	}{
+		{"0123456789abcdef0123456789abcdef", "12.0", "victory", 1700000000},
+		{"fedcba9876543210fedcba9876543210", "-9.5", "defeat", 1699990000},
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.GameID != w.id || e.Score != w.score || e.Result != w.result {
+			t.Errorf("entry %d = %+v, want id %s score %s result %s", i, e, w.id, w.score, w.result)
+		}
+		date, err := time.Parse(time.RFC3339, e.Date)
+		if err != nil || date.Unix() != w.unix {
+			t.Errorf("entry %d date = %q, want unix %d", i, e.Date, w.unix)
+		}
+	}
+
+	if got := extractGameEntries(readFixture(t, "list.html"), 1); len(got) != 1 {
+		t.Errorf("got %d entries with max 1, want 1", len(got))
+	}
+}
+
+func TestExtractPlayerAndEnemyData(t *testing.T) {
+	page := readFixture(t, "game.html")
+
+	tests := []struct {
+		result string
+		want   [6]string
+	}{
+		{"victory", [6]string{"Alpha", "Bravo", "1500", "1480", "+12", "-12"}},
+		{"defeat", [6]string{"Bravo", "Alpha", "1480", "1500", "-12", "+12"}},
+	}
+	for _, tt := range tests {
+		playerName, enemyName, playerElo, enemyElo, playerChange, enemyChange := extractPlayerAndEnemyData(page, tt.result)
+		got := [6]string{playerName, enemyName, playerElo, enemyElo, playerChange, enemyChange}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.result, got, tt.want)
+		}
+	}
+
+	if name, _, _, _, _, _ := extractPlayerAndEnemyData("<html></html>", "victory"); name != "" {
+		t.Errorf("got player %q from an empty page, want none", name)
+	}
+}
+
+func TestEugnetHistoryProvider(t *testing.T) {
+	server := newEugnetFixtureServer(t)
+	provider := newEugnetHistoryProvider(server.URL)
+
+	games, err := provider.FetchGameHistory("123", 0)
+	if err != nil {
+		t.Fatalf("FetchGameHistory: %v", err)
+	}
+	if len(games) != 2 {
+		t.Fatalf("got %d games, want 2", len(games))
+	}
+
+	first := games[0]
+	if first.Player != "Alpha" || first.Enemy != "Bravo" {
+		t.Errorf("first game players = %q vs %q, want Alpha vs Bravo", first.Player, first.Enemy)
+	}
+	if !reflect.DeepEqual(first.PlayerElo, []string{"1500", "1512"}) {
+		t.Errorf("first game player Elo = %v, want [1500 1512]", first.PlayerElo)
+	}
+	if !reflect.DeepEqual(first.EnemyElo, []string{"1480", "1468"}) {
+		t.Errorf("first game enemy Elo = %v, want [1480 1468]", first.EnemyElo)
+	}
+
+	// The details of the second game fail to load; the list data is kept.
+	second := games[1]
+	if second.Player != "" || second.Result != "defeat" || second.Score != "-9.5" {
+		t.Errorf("second game = %+v, want list data only", second)
+	}
+
+	if _, err := provider.FetchGameHistory("456", 0); err == nil {
+		t.Error("expected an error for a missing history page")
+	}
+}
+
+type staticHistoryProvider struct {
+	name  string
+	games []PlayerGame
+}
+
+func (p staticHistoryProvider) Name() string { return p.name }
+
+func (p staticHistoryProvider) FetchGameHistory(string, int) ([]PlayerGame, error) {
+	return p.games, nil
+}
+
+func TestGetPlayerGameHistoryMergesProviders(t *testing.T) {
+	saved := gameHistoryProviders
+	gameHistoryProviders = nil
+	t.Cleanup(func() { gameHistoryProviders = saved })
+
+	registerGameHistoryProvider(staticHistoryProvider{name: "first", games: []PlayerGame{
+		{GameID: "a", Date: "2024-01-02T10:00:00Z", Player: "Alpha"},
+	}})
+	registerGameHistoryProvider(staticHistoryProvider{name: "second", games: []PlayerGame{
+		{GameID: "a", Date: "2024-01-02T10:00:00Z"},
+		{GameID: "b", Date: "2024-01-03T10:00:00Z"},
+	}})
+
+	games := (&App{}).GetPlayerGameHistory("123")
+	if len(games) != 2 {
+		t.Fatalf("got %d games, want 2", len(games))
+	}
+	if games[0].GameID != "b" || games[0].Source != "second" {
+		t.Errorf("newest game = %+v, want b from second", games[0])
+	}
+	if games[1].Player != "Alpha" || games[1].Source != "first" {
+		t.Errorf("duplicate game = %+v, want the first provider's record", games[1])
+	}
+}
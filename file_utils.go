@@ -83,6 +83,54 @@ func getLocalAppDataDir(appName string, directory ...string) (string, error) {
 	return cacheDir, nil
 }
 
+// sanitizeFileName replaces characters Windows does not allow in file names.
+func sanitizeFileName(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+
+	sanitized = strings.TrimRight(sanitized, ". ")
+	if sanitized == "" {
+		return "_"
+	}
+
+	return sanitized
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// over path, so readers see either the old or the new content.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("syncing temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing temporary file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return fmt.Errorf("setting permissions: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing %s: %w", filepath.Base(path), err)
+	}
+
+	return nil
+}
+
 func writeEmptyCache(cacheFilePath string) error {
 	// Backwards-compat shim: keep behavior for existing callers.
 	// Prefer writeCache(cacheFilePath, fileInfo, nil) which includes modtime/size.
@@ -115,7 +163,11 @@ func processFile(filePath string, result *sync.Map) error {
 			if err := json.Unmarshal(cachedContent, &cached); err == nil {
 				if cached.ModTimeUnixNano == fileInfo.ModTime().UnixNano() && cached.Size == fileInfo.Size() {
 					if cached.Data != nil {
-						result.Store(filePath, *cached.Data)
+						data := *cached.Data
+						data.FilePath = filePath
+						data.FileName = filepath.Base(filePath)
+						data.Key = data.FileName
+						result.Store(filePath, data)
 					}
 					return nil
 				}
@@ -128,9 +180,7 @@ func processFile(filePath string, result *sync.Map) error {
 		return err
 	}
 
-	isTwoPlayerReplay := strings.Contains(content, `"NbMaxPlayer":"2"`)
-	isNetworkMode := strings.Contains(content, `"IsNetworkMode":"1"`)
-	if !isTwoPlayerReplay || !isNetworkMode {
+	if replayContentSkipReason(content) != "" {
 		return writeCache(cacheFilePath, fileInfo, nil)
 	}
 
@@ -147,10 +197,7 @@ func processFile(filePath string, result *sync.Map) error {
 	if !ok {
 		return writeCache(cacheFilePath, fileInfo, nil)
 	}
-	if _, exists := game["WithHost"]; exists {
-		return writeCache(cacheFilePath, fileInfo, nil)
-	}
-	if _, exists := game["ServerName"]; exists {
+	if replayLobbySkipReason(game) != "" {
 		return writeCache(cacheFilePath, fileInfo, nil)
 	}
 
@@ -14,6 +14,7 @@ import { DailyRecap } from './components/DailyRecap';
 import { useReplayContext } from './contexts/ReplayContext';
 import { Leaderboard } from './components/Leaderboard';
 import { GlobalStats } from './components/GlobalStats';
+import { TeamPool } from './components/TeamPool';
 
 function App() {
   const { directories, setDirectories, replays, stats, playerNamesMap, loading, refresh } =
@@ -31,9 +32,11 @@ function App() {
   useEffect(() => {
     const handler = () => refresh();
     EventsOn('replay-file-added', handler);
+    EventsOn('replay-file-removed', handler);
+    EventsOn('replay-files-changed', handler);
 
     return () => {
-      EventsOff('replay-file-added');
+      EventsOff('replay-file-added', 'replay-file-removed', 'replay-files-changed');
     };
   }, []);
 
@@ -138,6 +141,15 @@ function App() {
                           />
                         </div>
                       )
+                    },
+                    {
+                      key: '6',
+                      label: 'Team',
+                      children: (
+                        <div className="pt-4 mb-10">
+                          <TeamPool />
+                        </div>
+                      )
                     }
                   ]}
                   activeTabKey={activeTab}
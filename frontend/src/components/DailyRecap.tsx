@@ -206,8 +206,10 @@ export const DailyRecap = ({ replays }: DailyRecapProps) => {
           uniquePlayerIds.map(async (playerId) => {
             const playerReplays = replaysByPlayer.get(playerId) ?? [];
             const eugenPlayer = await GetEugenPlayer(playerId);
-            const rank = eugenPlayer?.ELO_LB_rank;
-            const currentElo = safeParseFloat(eugenPlayer?.ELO);
+            const rank = eugenPlayer?.leaderboardRank
+              ? String(eugenPlayer.leaderboardRank)
+              : undefined;
+            const currentElo = eugenPlayer?.elo || undefined;
 
             const stats = calculateStats(playerReplays, rank, currentElo, leaderboard);
             if (stats) newStats[playerId] = stats;
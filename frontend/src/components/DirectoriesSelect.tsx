@@ -1,5 +1,5 @@
-import { Select, SelectProps } from 'antd';
-import { GetWarnoSaveFolders } from '../../wailsjs/go/main/App';
+import { Select, SelectProps, message } from 'antd';
+import { GetSettings, GetWarnoSaveFolders, SaveSettings } from '../../wailsjs/go/main/App';
 import { useEffect, useState } from 'react';
 
 type DirectoriesSelectProps = {
@@ -26,8 +26,10 @@ export const DirectoriesSelect = ({ directories, setDirectories }: DirectoriesSe
 
       setOptions(newOptions);
 
-  
-      if (directories.length === 0 && newOptions.length > 0) {
+      const settings = await GetSettings();
+      if (settings.folders?.length) {
+        setDirectories(settings.folders);
+      } else if (directories.length === 0 && newOptions.length > 0) {
         setDirectories(newOptions.map((option) => option.value));
       }
     };
@@ -35,8 +37,15 @@ export const DirectoriesSelect = ({ directories, setDirectories }: DirectoriesSe
     getFolders();
   }, []);
 
-  const handleChange = (value: string[]) => {
+  const handleChange = async (value: string[]) => {
     setDirectories(value);
+
+    try {
+      const settings = await GetSettings();
+      await SaveSettings({ ...settings, folders: value } as any);
+    } catch (err) {
+      message.error(String(err));
+    }
   };
 
   return (
@@ -12,14 +12,20 @@ import {
   Table
 } from 'antd';
 import type { ColumnsType } from 'antd/es/table';
-import { GetRankedReplaysAnalytics } from '../../wailsjs/go/main/App';
+import {
+  GetLocalRankedReplaysAnalytics,
+  GetRankedReplaysAnalytics
+} from '../../wailsjs/go/main/App';
 import { main } from '../../wailsjs/go/models';
+import { useReplayContext } from '../contexts/ReplayContext';
 
 import divisionsData from '../data/divisions.json';
 import mapsData from '../data/maps.json';
 
 type DivisionRow = main.DivisionWinrateRow;
 
+type AnalyticsSource = 'global' | 'local';
+
 const formatWinRate = (value?: number) => {
   if (value == null || !Number.isFinite(value)) return '-';
   // Backends sometimes return either 0..1 or 0..100. Handle both.
@@ -39,7 +45,9 @@ const mapLabel = (mapKey: string, mapNameByKey: Map<string, string>) => {
 };
 
 export const GlobalStats = () => {
+  const { directories } = useReplayContext();
   const [loading, setLoading] = useState(false);
+  const [source, setSource] = useState<AnalyticsSource>('global');
   const [analytics, setAnalytics] = useState<main.RankedReplaysAnalyticsResponse | null>(null);
 
   const [filterMaxRank, setFilterMaxRank] = useState<number>(0);
@@ -50,10 +58,18 @@ export const GlobalStats = () => {
   const [selectedOpponent, setSelectedOpponent] = useState<number | null>(null); // optional
   const [selectedMap, setSelectedMap] = useState<string | null>(null); // optional
 
-  const fetchAnalytics = async (maxRank: number, minElo: number) => {
+  const fetchAnalytics = async (
+    maxRank: number,
+    minElo: number,
+    from: AnalyticsSource = source
+  ) => {
     try {
       setLoading(true);
-      const data = await GetRankedReplaysAnalytics(maxRank, minElo);
+      const filter = main.RankedReplaysAnalyticsFilter.createFrom({ maxRank, minElo });
+      const data =
+        from === 'local'
+          ? await GetLocalRankedReplaysAnalytics(directories, filter)
+          : await GetRankedReplaysAnalytics(filter);
       setAnalytics(data);
     } finally {
       setLoading(false);
@@ -256,6 +272,21 @@ export const GlobalStats = () => {
           </div>
 
           <div className="flex-1 flex flex-col md:flex-row gap-4 justify-end">
+            <div>
+              <div className="text-xs text-neutral-400 mb-1">Games</div>
+              <Select<AnalyticsSource>
+                style={{ width: 160 }}
+                value={source}
+                options={[
+                  { value: 'global', label: 'All players' },
+                  { value: 'local', label: 'My replays' }
+                ]}
+                onChange={(value) => {
+                  setSource(value);
+                  void fetchAnalytics(filterMaxRank, filterMinElo, value);
+                }}
+              />
+            </div>
             <div>
               <div className="text-xs text-neutral-400 mb-1">Players rank</div>
               <Select<number>
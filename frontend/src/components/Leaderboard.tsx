@@ -6,7 +6,7 @@ import {
   SaveSettings
 } from '../../wailsjs/go/main/App';
 import { main } from '../../wailsjs/go/models';
-import { Input } from 'antd';
+import { Input, message } from 'antd';
 import { useDebounce } from '../hooks/useDebounce';
 import { TableVirtuoso, TableComponents } from 'react-virtuoso';
 import { StarFilled, StarOutlined } from '@ant-design/icons';
@@ -122,6 +122,7 @@ export const Leaderboard = ({ onOpenPlayer }: { onOpenPlayer?: (playerId: string
           } catch (e) {
             // Revert on failure.
             setFavoritePlayerIds(prev);
+            message.error(String(e));
           }
         })();
 
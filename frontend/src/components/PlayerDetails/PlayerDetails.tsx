@@ -16,17 +16,13 @@ import { Replay } from '../../parsers/replaysParser';
 
 dayjs.extend(relativeTime);
 
-const getRankedWinrate = (eugenPlayer?: main.EugenPlayer) => {
+const getRankedWinrate = (eugenPlayer?: main.EugenPlayerProfile) => {
   if (!eugenPlayer) return <span>N/A</span>;
 
-  const wins = Number(eugenPlayer.ranked_win) || 0;
-  const losses = Number(eugenPlayer.ranked_loss) || 0;
-  const fouls = Number(eugenPlayer.ranked_foul) || 0;
+  const { wins, losses, played, winRate } = eugenPlayer.ranked;
+  if (played === 0) return <span>N/A</span>;
 
-  const totalGames = wins + losses + fouls;
-  if (totalGames === 0) return <span>N/A</span>;
-
-  const winrate = ((wins / totalGames) * 100).toFixed(0);
+  const winrate = (winRate * 100).toFixed(0);
 
   return (
     <div className="flex items-center gap-1">
@@ -71,7 +67,7 @@ export const PlayerDetails = ({
   playerNamesMap: PlayerNamesMap;
 }) => {
   const [steamPlayer, setSteamPlayer] = useState<main.SteamPlayer>();
-  const [eugenPlayer, setEugenPlayer] = useState<main.EugenPlayer>();
+  const [eugenPlayer, setEugenPlayer] = useState<main.EugenPlayerProfile>();
   const [isSteamPlayerLoading, setSteamPlayerLoading] = useState<boolean>(true);
 
   useEffect(() => {
@@ -98,7 +94,7 @@ export const PlayerDetails = ({
       title={
         <div className="flex gap-2 items-center mb-2">
           <div className="flex items-center justify-center w-10 h-10">
-            {isSteamPlayerLoading ? <Spin /> : <Avatar src={steamPlayer?.avatarmedium} size={38} />}
+            {isSteamPlayerLoading ? <Spin /> : <Avatar src={steamPlayer?.localAvatar || steamPlayer?.avatarmedium} size={38} />}
           </div>
 
           <div className="flex gap-2 items-center">
@@ -133,8 +129,8 @@ export const PlayerDetails = ({
 
             <RankIndicator
               rankMinMax={rankMinMax}
-              rank={parseInt(eugenPlayer?.ELO_LB_rank ?? '0')}
-              delta={parseInt(eugenPlayer?.ELO_LB_delta_rank ?? '0')}
+              rank={eugenPlayer?.leaderboardRank ?? 0}
+              delta={eugenPlayer?.leaderboardRankDelta ?? 0}
             />
             <Tag bordered={false}>#{player?.id}</Tag>
           </div>
@@ -170,7 +166,7 @@ export const PlayerDetails = ({
               label: 'ELO',
               children: eugenPlayer ? (
                 <div className="flex flex-col gap-1">
-                  {parseInt(eugenPlayer.ELO)} ({parseInt(eugenPlayer.ELO_LB_delta_value)})
+                  {Math.trunc(eugenPlayer.elo)} ({Math.trunc(eugenPlayer.leaderboardEloDelta)})
                 </div>
               ) : (
                 'N/A'
@@ -1,28 +1,34 @@
 import dayjs from 'dayjs';
 import relativeTime from 'dayjs/plugin/relativeTime';
 import { useEffect, useState } from 'react';
-import { Button, Input, Typography } from 'antd';
+import { Button, Input, Select, Tag, Typography } from 'antd';
 import { DeleteOutlined, SendOutlined } from '@ant-design/icons';
 import { Player } from '../../parsers/playersParser';
-import { CreatePlayerNote, DeletePlayerNote, GetPlayerNotes } from '../../../wailsjs/go/main/App';
+import {
+  CreatePlayerNote,
+  DeletePlayerNote,
+  GetPlayerNotes,
+  UpdatePlayerNote,
+} from '../../../wailsjs/go/main/App';
+import { main } from '../../../wailsjs/go/models';
 
 dayjs.extend(relativeTime);
 
-type PlayerNote = {
-  id: string;
-  content: string;
-  createdAt: string;
-};
-
 export const PlayerNotes = ({ player }: { player: Player }) => {
   const [note, setNote] = useState<string>('');
-  const [notes, setNotes] = useState<PlayerNote[]>([]);
+  const [tags, setTags] = useState<string[]>([]);
+  const [notes, setNotes] = useState<main.PlayerNote[]>([]);
+  const [error, setError] = useState<string>('');
 
   const fetchNotes = async () => {
-    const data = await GetPlayerNotes(player.id);
-    const notes = JSON.parse(data);
+    try {
+      const notes = await GetPlayerNotes(player.id);
 
-    setNotes(notes || []);
+      setNotes(notes || []);
+      setError('');
+    } catch (err) {
+      setError(String(err));
+    }
   };
 
   useEffect(() => {
@@ -30,14 +36,24 @@ export const PlayerNotes = ({ player }: { player: Player }) => {
   }, [player.id]);
 
   const handleNewNote = async () => {
-    await CreatePlayerNote(player.id, note);
+    await CreatePlayerNote(player.id, note, tags, '');
     fetchNotes();
 
     setNote('');
+    setTags([]);
+  };
+
+  const handleEditNote = async ({ id, tags, replayId }: main.PlayerNote, content: string) => {
+    await UpdatePlayerNote(player.id, id, content, tags || [], replayId || '');
+    fetchNotes();
   };
 
   const handleDeleteNote = async (id: string) => {
-    await DeletePlayerNote(player.id, id);
+    try {
+      await DeletePlayerNote(player.id, id);
+    } catch (err) {
+      setError(String(err));
+    }
     fetchNotes();
   };
 
@@ -47,14 +63,28 @@ export const PlayerNotes = ({ player }: { player: Player }) => {
         Notes
       </Typography.Title>
 
+      {error && (
+        <Typography.Text type="danger" className="block mb-2">
+          {error}
+        </Typography.Text>
+      )}
+
       <ul className="flex flex-col list-disc list-inside gap-1">
-        {notes.map(({ id, content, createdAt }) => (
-          <li key={id}>
+        {notes.map((item) => (
+          <li key={item.id}>
             <Typography.Text type="secondary">
-              {dayjs(createdAt).format('DD/MM/YYYY HH:mm')} ({dayjs(createdAt).fromNow()})
+              {dayjs(item.createdAt).format('DD/MM/YYYY HH:mm')} ({dayjs(item.createdAt).fromNow()})
+              {item.updatedAt && ` (edited ${dayjs(item.updatedAt).fromNow()})`}
             </Typography.Text>{' '}
-            {content}
-            <DeleteOutlined className="ml-2 cursor-pointer" onClick={() => handleDeleteNote(id)} />
+            <Typography.Text editable={{ onChange: (content) => handleEditNote(item, content) }}>
+              {item.content}
+            </Typography.Text>
+            {item.tags?.map((tag) => (
+              <Tag key={tag} className="ml-1">
+                {tag}
+              </Tag>
+            ))}
+            <DeleteOutlined className="ml-2 cursor-pointer" onClick={() => handleDeleteNote(item.id)} />
           </li>
         ))}
       </ul>
@@ -70,6 +100,14 @@ export const PlayerNotes = ({ player }: { player: Player }) => {
             }
           }}
         />
+        <Select
+          mode="tags"
+          value={tags}
+          onChange={setTags}
+          placeholder="Tags"
+          className="min-w-40"
+          open={false}
+        />
         <Button onClick={() => handleNewNote()} icon={<SendOutlined />} />
       </div>
     </div>
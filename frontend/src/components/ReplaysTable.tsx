@@ -1,15 +1,30 @@
-import { Button, Table } from 'antd';
+import { Button, Dropdown, Select, Table, message } from 'antd';
 import dayjs from 'dayjs';
 import relativeTime from 'dayjs/plugin/relativeTime';
 import { Replay } from '../parsers/replaysParser';
 import { ColumnType } from 'antd/es/table';
 import { Input } from 'antd';
 import { useState } from 'react';
-import { CopyOutlined, DownloadOutlined, LinkOutlined } from '@ant-design/icons';
+import {
+  CopyOutlined,
+  DeleteOutlined,
+  DownloadOutlined,
+  EditOutlined,
+  InboxOutlined,
+  LinkOutlined
+} from '@ant-design/icons';
 import duration from 'dayjs/plugin/duration';
 import CopyToClipboard from 'react-copy-to-clipboard';
 import { transliterate } from '../helpers/transliterate';
 import { downloadCsv, toCsv } from '../helpers/exportCsv';
+import {
+  ArchiveReplays,
+  DeleteReplays,
+  RenameReplays,
+  SelectArchiveFolder,
+  SetReplayTags
+} from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
 
 dayjs.extend(relativeTime);
 dayjs.extend(duration);
@@ -19,6 +34,32 @@ const { Search } = Input;
 const waryesDeckBuilderUrl = (deckCode: string) =>
   `https://waryes.com/deck-builder?code=${encodeURIComponent(deckCode)}`;
 
+const renameTemplate = '{date}_{me}_vs_{enemy}_{map}_{result}';
+
+const TagsCell = ({ replay }: { replay: Replay }) => {
+  const [tags, setTags] = useState<string[]>(replay.tags);
+
+  const handleChange = async (value: string[]) => {
+    try {
+      setTags((await SetReplayTags(replay.id, value)) || []);
+    } catch (err) {
+      message.error(String(err));
+    }
+  };
+
+  return (
+    <Select
+      mode="tags"
+      size="small"
+      variant="borderless"
+      className="min-w-32"
+      value={tags}
+      onChange={handleChange}
+      placeholder="Add tag"
+    />
+  );
+};
+
 const getColumns = (onOpenPlayer?: (playerId: string) => void): ColumnType<Replay>[] => [
   {
     title: 'Date',
@@ -149,6 +190,12 @@ const getColumns = (onOpenPlayer?: (playerId: string) => void): ColumnType<Repla
     dataIndex: 'eloChange',
     key: 'eloChange',
     sorter: (a: Replay, b: Replay) => a.eloChange - b.eloChange
+  },
+  {
+    title: 'Tags',
+    dataIndex: 'tags',
+    key: 'tags',
+    render: (_: string[], record) => <TagsCell key={record.id} replay={record} />
   }
 ];
 
@@ -160,14 +207,43 @@ export const ReplaysTable = ({
   onOpenPlayer?: (playerId: string) => void;
 }) => {
   const [searchText, setSearchText] = useState('');
+  const [selectedPaths, setSelectedPaths] = useState<string[]>([]);
 
   const handleSearch = (value: string) => {
     setSearchText(value);
   };
 
-  const filteredReplays = replays.filter((replay) =>
-    transliterate(replay.enemyName.toLowerCase()).includes(transliterate(searchText.toLowerCase()))
-  );
+  const filteredReplays = replays.filter((replay) => {
+    const query = transliterate(searchText.toLowerCase());
+    return (
+      transliterate(replay.enemyName.toLowerCase()).includes(query) ||
+      replay.tags.some((tag) => tag.includes(query))
+    );
+  });
+
+  const reportChanges = (changes: main.ReplayFileChange[]) => {
+    const failed = changes.filter((change) => change.error);
+    if (failed.length > 0) {
+      message.error(`${failed.length} replay(s) failed: ${failed[0].error}`);
+    }
+    setSelectedPaths([]);
+  };
+
+  const handleRename = async () => reportChanges(await RenameReplays(selectedPaths, renameTemplate));
+
+  const handleArchive = async (chooseFolder: boolean) => {
+    const folder = chooseFolder ? await SelectArchiveFolder() : '';
+    if (chooseFolder && !folder) return;
+    reportChanges(await ArchiveReplays(selectedPaths, folder));
+  };
+
+  const handleDelete = async () => {
+    try {
+      reportChanges(await DeleteReplays(selectedPaths));
+    } catch (err) {
+      message.error(String(err));
+    }
+  };
 
   const exportCsv = () => {
     const rows = filteredReplays.map((r) => ({
@@ -208,7 +284,35 @@ export const ReplaysTable = ({
   return (
     <>
       <div className="flex gap-2 mb-2">
-        <Search placeholder="Find enemy" onSearch={handleSearch} className="flex-1" allowClear />
+        <Search
+          placeholder="Find enemy or tag"
+          onSearch={handleSearch}
+          className="flex-1"
+          allowClear
+        />
+        <Button
+          icon={<EditOutlined />}
+          disabled={selectedPaths.length === 0}
+          onClick={handleRename}
+          title={`Rename as ${renameTemplate}`}>
+          Rename
+        </Button>
+        <Dropdown.Button
+          disabled={selectedPaths.length === 0}
+          onClick={() => handleArchive(false)}
+          menu={{
+            items: [{ key: 'folder', label: 'Archive to folder...' }],
+            onClick: () => handleArchive(true)
+          }}>
+          <InboxOutlined /> Archive
+        </Dropdown.Button>
+        <Button
+          danger
+          icon={<DeleteOutlined />}
+          disabled={selectedPaths.length === 0}
+          onClick={handleDelete}>
+          Delete
+        </Button>
         <Button icon={<DownloadOutlined />} onClick={exportCsv}>
           Export CSV
         </Button>
@@ -218,7 +322,11 @@ export const ReplaysTable = ({
         columns={getColumns(onOpenPlayer)}
         size="small"
         pagination={false}
-        rowKey="fileName"
+        rowKey="filePath"
+        rowSelection={{
+          selectedRowKeys: selectedPaths,
+          onChange: (keys) => setSelectedPaths(keys as string[])
+        }}
       />
     </>
   );
@@ -1,4 +1,4 @@
-import { Select } from 'antd';
+import { Select, message } from 'antd';
 import dayjs from 'dayjs';
 import { useEffect, useState } from 'react';
 import { GetSettings, SaveSettings } from '../../../wailsjs/go/main/App';
@@ -60,7 +60,12 @@ export const SeasonSelect = () => {
       updatedSettings = rest;
     }
 
-    await SaveSettings(updatedSettings);
+    try {
+      await SaveSettings(updatedSettings);
+    } catch (err) {
+      message.error(String(err));
+      return;
+    }
     refreshStats();
   };
 
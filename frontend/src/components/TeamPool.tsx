@@ -0,0 +1,191 @@
+import { useEffect, useState } from 'react';
+import {
+  Button,
+  Card,
+  Checkbox,
+  Col,
+  Input,
+  Popconfirm,
+  Row,
+  Statistic,
+  Table,
+  message
+} from 'antd';
+import type { ColumnsType } from 'antd/es/table';
+import dayjs from 'dayjs';
+import { DeleteOutlined, DownloadOutlined, UploadOutlined } from '@ant-design/icons';
+import {
+  ExportReplayBundle,
+  GetTeamPoolStats,
+  ImportReplayBundle,
+  RemoveTeamPoolMember
+} from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { useReplayContext } from '../contexts/ReplayContext';
+import { formatDuration } from '../helpers/formatDuration';
+
+const formatWinRate = (value: number) => `${(value * 100).toFixed(1)}%`;
+
+const formatDate = (value?: string) => (value ? dayjs(value).format('DD/MM/YYYY') : '-');
+
+export const TeamPool = () => {
+  const { directories } = useReplayContext();
+  const [stats, setStats] = useState<main.TeamPoolStats>();
+  const [member, setMember] = useState('');
+  const [includeFiles, setIncludeFiles] = useState(true);
+  const [busy, setBusy] = useState(false);
+
+  const loadStats = async () => {
+    setStats(await GetTeamPoolStats());
+  };
+
+  useEffect(() => {
+    void loadStats();
+  }, []);
+
+  const handleExport = async () => {
+    setBusy(true);
+    try {
+      const path = await ExportReplayBundle(directories, member.trim(), includeFiles);
+      if (path) {
+        message.success(`Replays exported to ${path}`);
+      }
+    } catch (err) {
+      message.error(String(err));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleImport = async () => {
+    setBusy(true);
+    try {
+      const result = await ImportReplayBundle();
+      if (result.member) {
+        message.success(
+          `${result.member}: ${result.imported} games imported, ` +
+            `${result.duplicates} already in the pool`
+        );
+        await loadStats();
+      }
+    } catch (err) {
+      message.error(String(err));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleRemove = async (name: string) => {
+    try {
+      await RemoveTeamPoolMember(name);
+      await loadStats();
+    } catch (err) {
+      message.error(String(err));
+    }
+  };
+
+  const columns: ColumnsType<main.MemberPoolStats> = [
+    { title: 'Member', dataIndex: 'member', key: 'member' },
+    {
+      title: 'Games',
+      key: 'games',
+      render: (_, row) => row.stats.games,
+      sorter: (a, b) => a.stats.games - b.stats.games
+    },
+    {
+      title: 'W / L / D',
+      key: 'results',
+      render: (_, row) => `${row.stats.wins} / ${row.stats.losses} / ${row.stats.draws}`
+    },
+    {
+      title: 'Win rate',
+      key: 'winRate',
+      render: (_, row) => formatWinRate(row.stats.winRate),
+      sorter: (a, b) => a.stats.winRate - b.stats.winRate
+    },
+    {
+      title: 'Avg. duration',
+      key: 'averageDuration',
+      render: (_, row) => formatDuration(row.stats.averageDuration)
+    },
+    {
+      title: 'Games played',
+      key: 'period',
+      render: (_, row) =>
+        `${formatDate(row.stats.firstGameAt)} - ${formatDate(row.stats.lastGameAt)}`
+    },
+    {
+      title: '',
+      key: 'actions',
+      width: 40,
+      render: (_, row) => (
+        <Popconfirm
+          title={`Remove the games of ${row.member} from the pool?`}
+          onConfirm={() => handleRemove(row.member)}>
+          <Button size="small" type="text" icon={<DeleteOutlined />} />
+        </Popconfirm>
+      )
+    }
+  ];
+
+  return (
+    <div className="flex flex-col gap-4">
+      <Card>
+        <div className="flex flex-col md:flex-row gap-4 md:items-end">
+          <div>
+            <div className="text-xs text-neutral-400 mb-1">Your name in the team</div>
+            <Input
+              style={{ width: 220 }}
+              value={member}
+              onChange={(e) => setMember(e.target.value)}
+              placeholder="Member name"
+            />
+          </div>
+          <Checkbox checked={includeFiles} onChange={(e) => setIncludeFiles(e.target.checked)}>
+            Include replay files
+          </Checkbox>
+          <Button
+            icon={<DownloadOutlined />}
+            disabled={!member.trim()}
+            loading={busy}
+            onClick={handleExport}>
+            Export my replays
+          </Button>
+          <Button icon={<UploadOutlined />} loading={busy} onClick={handleImport}>
+            Import bundle
+          </Button>
+        </div>
+      </Card>
+
+      <Card title="Team">
+        <Row gutter={[16, 16]}>
+          <Col xs={12} md={4}>
+            <Statistic title="Games" value={stats?.team.games ?? 0} />
+          </Col>
+          <Col xs={12} md={4}>
+            <Statistic title="Wins" value={stats?.team.wins ?? 0} />
+          </Col>
+          <Col xs={12} md={4}>
+            <Statistic title="Losses" value={stats?.team.losses ?? 0} />
+          </Col>
+          <Col xs={12} md={4}>
+            <Statistic title="Win rate" value={formatWinRate(stats?.team.winRate ?? 0)} />
+          </Col>
+          <Col xs={12} md={4}>
+            <Statistic title="Games between members" value={stats?.internalGames ?? 0} />
+          </Col>
+        </Row>
+      </Card>
+
+      <Card title="Members">
+        <Table
+          size="small"
+          rowKey="member"
+          columns={columns}
+          dataSource={stats?.members ?? []}
+          pagination={false}
+        />
+      </Card>
+    </div>
+  );
+};
@@ -1,45 +1,91 @@
 import { useEffect, useState } from 'react';
-import { GetAppVersions } from '../../wailsjs/go/main/App';
-import { Button, Tag } from 'antd';
+import { CheckForUpdate, DownloadUpdate } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { Button, Popover, Tag, Typography } from 'antd';
 import dayjs from 'dayjs';
 import relativeTime from 'dayjs/plugin/relativeTime';
-import { LinkOutlined } from '@ant-design/icons';
-
-import { versionOutdated } from './../helpers/version';
+import { DownloadOutlined, LinkOutlined } from '@ant-design/icons';
 
 dayjs.extend(relativeTime);
 
 export const Version = () => {
-  const [versions, setVersions] = useState<[string, string]>();
+  const [status, setStatus] = useState<main.UpdateStatus>();
+  const [downloading, setDownloading] = useState(false);
+  const [error, setError] = useState<string>('');
 
   useEffect(() => {
-    const fetchAppVersions = async (): Promise<void> => {
-      const [appVersion, latestVersion] = await GetAppVersions();
-      setVersions([appVersion, latestVersion]);
+    const fetchUpdateStatus = async (): Promise<void> => {
+      try {
+        setStatus(await CheckForUpdate());
+      } catch (err) {
+        console.error(err);
+      }
     };
 
-    fetchAppVersions();
+    fetchUpdateStatus();
 
     const interval = setInterval(() => {
-      fetchAppVersions();
+      fetchUpdateStatus();
     }, 3600000);
 
     return () => clearInterval(interval);
   }, []);
 
-  return versions && versionOutdated(...versions) ? (
-    <Tag>
-      <div className="flex items-center">
-        New version available: {versions[1]}
-        <Button
-          type="link"
-          size="small"
-          href="https://github.com/Kraku/warno-replays-analyser/releases/latest"
-          target="_blank"
-          rel="noopener noreferrer"
-          icon={<LinkOutlined />}
-        />
-      </div>
-    </Tag>
-  ) : null;
+  const handleDownload = async () => {
+    setDownloading(true);
+    try {
+      setStatus(await DownloadUpdate());
+      setError('');
+    } catch (err) {
+      setError(String(err));
+    } finally {
+      setDownloading(false);
+    }
+  };
+
+  const latest = status?.latest;
+  if (!status?.updateAvailable || !latest) {
+    return null;
+  }
+
+  const staged = status.stagedVersion === latest.version;
+
+  return (
+    <Popover
+      placement="bottomRight"
+      title={`${latest.name || latest.version}${latest.publishedAt ? `, ${dayjs(latest.publishedAt).fromNow()}` : ''}`}
+      content={
+        <div className="max-w-md max-h-96 overflow-y-auto">
+          <Typography.Paragraph className="whitespace-pre-wrap">{latest.notes}</Typography.Paragraph>
+          {(error || status.updateError) && (
+            <Typography.Text type="danger">{error || status.updateError}</Typography.Text>
+          )}
+        </div>
+      }>
+      <Tag>
+        <div className="flex items-center">
+          {staged
+            ? `Version ${latest.version} will be installed on restart`
+            : `New version available: ${latest.version}`}
+          {!staged && latest.assetName ? (
+            <Button
+              type="link"
+              size="small"
+              loading={downloading}
+              onClick={handleDownload}
+              icon={<DownloadOutlined />}
+            />
+          ) : null}
+          <Button
+            type="link"
+            size="small"
+            href={latest.url}
+            target="_blank"
+            rel="noopener noreferrer"
+            icon={<LinkOutlined />}
+          />
+        </div>
+      </Tag>
+    </Popover>
+  );
 };
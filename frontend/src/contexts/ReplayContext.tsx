@@ -148,6 +148,7 @@ export const ReplayProvider = ({ children }: { children: React.ReactNode }) => {
           player1Division,
           player2Division,
           map: mapKey,
+          version: replay.version || undefined,
           duration,
           winnerPlayerEugenId,
           submittedByEugenId: player1EugenId,
@@ -162,7 +163,9 @@ export const ReplayProvider = ({ children }: { children: React.ReactNode }) => {
       const rankedReplays = buildRankedReplayInputs(replays);
 
       await SendRankedReplaysToAPI(rankedReplays);
-      await GetRankedReplaysAnalytics(0, 0);
+      await GetRankedReplaysAnalytics(
+        main.RankedReplaysAnalyticsFilter.createFrom({ maxRank: 0, minElo: 0 })
+      );
     } catch (error) {
       console.error('Error sending ranked replays to API:', error);
     }
@@ -1,8 +1,24 @@
-import { Button, Checkbox, Drawer, Form, Select } from 'antd';
+import { Alert, Button, Checkbox, Drawer, Form, Input, Select, Space } from 'antd';
 
 import { useEffect, useState } from 'react';
 import { useForm } from 'antd/es/form/Form';
-import { SaveSettings, GetSettings, GetPlayerIdsOptions } from '../../wailsjs/go/main/App';
+import { DeleteOutlined, FolderAddOutlined } from '@ant-design/icons';
+import {
+  AddReplayFolder,
+  CloneSettingsProfile,
+  DeleteAppId,
+  DeleteSettingsProfile,
+  GetPlayerIdsOptions,
+  GetSettings,
+  GetSettingsProfiles,
+  GetTelemetryPayload,
+  RegenerateAppId,
+  RemoveReplayFolder,
+  SaveSettings,
+  SelectReplayFolder,
+  SwitchSettingsProfile,
+  UpdateReplayFolder
+} from '../../wailsjs/go/main/App';
 import { main } from '../../wailsjs/go/models';
 
 export const SettingsDrawer = ({
@@ -15,17 +31,38 @@ export const SettingsDrawer = ({
   const [form] = useForm();
   const [options, setOptions] = useState<main.PlayerIdsOption[]>([]);
   const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string>('');
+  const [profiles, setProfiles] = useState<main.SettingsProfile[]>([]);
+  const [activeProfile, setActiveProfile] = useState<string>();
+  const [profileName, setProfileName] = useState<string>('');
+  const [replayFolders, setReplayFolders] = useState<main.ReplayFolder[]>([]);
+  const [telemetryDisabled, setTelemetryDisabled] = useState(false);
+  const [updateChannel, setUpdateChannel] = useState('stable');
+  const [telemetry, setTelemetry] = useState<main.TelemetryInfo>();
 
   useEffect(() => {
     const initialize = async () => {
       try {
-        const [settings, data] = await Promise.all([GetSettings(), GetPlayerIdsOptions()]);
+        const [settings, data, profiles, telemetry] = await Promise.all([
+          GetSettings(),
+          GetPlayerIdsOptions(),
+          GetSettingsProfiles(),
+          GetTelemetryPayload()
+        ]);
 
         form.setFieldsValue({
           playerIds: settings.playerIds
         });
 
         setOptions(data);
+        setProfiles(profiles);
+        setActiveProfile(settings.activeProfile);
+        setReplayFolders(settings.replayFolders || []);
+        setTelemetryDisabled(!!settings.telemetryDisabled);
+        setUpdateChannel(settings.updateChannel || 'stable');
+        setTelemetry(telemetry);
+      } catch (err) {
+        setError(String(err));
       } finally {
         setLoading(false);
       }
@@ -40,13 +77,57 @@ export const SettingsDrawer = ({
 
     const params = {
       ...settings,
-      playerIds
+      playerIds,
+      telemetryDisabled,
+      updateChannel
     };
 
-    await SaveSettings(params);
+    try {
+      await SaveSettings(params);
+    } catch (err) {
+      setError(String(err));
+      return;
+    }
     onSave();
   };
 
+  const handleProfileChange = async (change: () => Promise<main.Settings>) => {
+    try {
+      await change();
+    } catch (err) {
+      setError(String(err));
+      return;
+    }
+    onSave();
+  };
+
+  const handleFolderChange = async (change: () => Promise<main.Settings>) => {
+    try {
+      const settings = await change();
+      setReplayFolders(settings.replayFolders || []);
+      setError('');
+    } catch (err) {
+      setError(String(err));
+    }
+  };
+
+  const handleAddFolder = async () => {
+    const path = await SelectReplayFolder();
+    if (path) {
+      handleFolderChange(() => AddReplayFolder(path, ''));
+    }
+  };
+
+  const handleAppIdChange = async (change: () => Promise<unknown>) => {
+    try {
+      await change();
+      setTelemetry(await GetTelemetryPayload());
+      setError('');
+    } catch (err) {
+      setError(String(err));
+    }
+  };
+
   return (
     <Drawer
       title="Settings"
@@ -62,7 +143,69 @@ export const SettingsDrawer = ({
           </Button>
         </div>
       }>
+      {error && <Alert type="error" message={error} className="mb-4" showIcon />}
       <Form layout="vertical" form={form}>
+        <Form.Item label="Profile" extra="Each profile keeps its own folders, accounts, date range and favorites.">
+          <Select
+            value={activeProfile}
+            options={profiles.map(({ name }) => ({ label: name, value: name }))}
+            onChange={(name) => handleProfileChange(() => SwitchSettingsProfile(name))}
+          />
+          <Space.Compact className="w-full mt-2">
+            <Input
+              value={profileName}
+              onChange={(e) => setProfileName(e.target.value)}
+              placeholder="New profile name"
+            />
+            <Button
+              disabled={!profileName || !activeProfile}
+              onClick={() => handleProfileChange(() => CloneSettingsProfile(activeProfile!, profileName))}>
+              Clone
+            </Button>
+            <Button
+              danger
+              disabled={!activeProfile || profiles.length < 2}
+              onClick={() => handleProfileChange(() => DeleteSettingsProfile(activeProfile!))}>
+              Delete
+            </Button>
+          </Space.Compact>
+        </Form.Item>
+        <Form.Item
+          label="Replay folders"
+          extra="Extra folders scanned next to the Steam save folders, such as downloaded tournament replays.">
+          <ul className="flex flex-col gap-1 mb-2">
+            {replayFolders.map((folder) => (
+              <li key={folder.path} className="flex items-center gap-2">
+                <Checkbox
+                  checked={folder.enabled}
+                  onChange={(e) =>
+                    handleFolderChange(() =>
+                      UpdateReplayFolder(folder.path, folder.label || '', e.target.checked)
+                    )
+                  }
+                />
+                <Input
+                  size="small"
+                  defaultValue={folder.label}
+                  placeholder="Label"
+                  className="w-32"
+                  onBlur={(e) =>
+                    e.target.value !== (folder.label || '') &&
+                    handleFolderChange(() => UpdateReplayFolder(folder.path, e.target.value, folder.enabled))
+                  }
+                />
+                <span className="truncate text-neutral-500">{folder.path}</span>
+                <DeleteOutlined
+                  className="cursor-pointer"
+                  onClick={() => handleFolderChange(() => RemoveReplayFolder(folder.path))}
+                />
+              </li>
+            ))}
+          </ul>
+          <Button icon={<FolderAddOutlined />} onClick={handleAddFolder}>
+            Add folder
+          </Button>
+        </Form.Item>
         <Form.Item
           label="Player IDs"
           name="playerIds"
@@ -81,6 +224,33 @@ export const SettingsDrawer = ({
             }))}
           />
         </Form.Item>
+        <Form.Item label="Updates" extra="Pre-release builds get new features first but may be less stable.">
+          <Select
+            value={updateChannel}
+            onChange={setUpdateChannel}
+            options={[
+              { label: 'Stable releases', value: 'stable' },
+              { label: 'Pre-releases', value: 'prerelease' }
+            ]}
+          />
+        </Form.Item>
+        <Form.Item
+          label="Telemetry"
+          extra="On startup the app sends this anonymous event so we know how many installs use each version.">
+          <Checkbox checked={!telemetryDisabled} onChange={(e) => setTelemetryDisabled(!e.target.checked)}>
+            Send the startup event
+          </Checkbox>
+          <pre className="text-xs bg-neutral-800 p-2 my-2 overflow-x-auto">
+            {telemetry?.endpoint ? `POST ${telemetry.endpoint}\n` : ''}
+            {JSON.stringify(telemetry?.payload, null, 2)}
+          </pre>
+          <Space>
+            <Button onClick={() => handleAppIdChange(RegenerateAppId)}>Regenerate app ID</Button>
+            <Button danger onClick={() => handleAppIdChange(DeleteAppId)}>
+              Delete app ID
+            </Button>
+          </Space>
+        </Form.Item>
       </Form>
     </Drawer>
   );
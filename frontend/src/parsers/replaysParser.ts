@@ -48,7 +48,9 @@ export type CommonReplayData = {
   duration: number;
   mapKey: string;
   map: string;
+  version: string;
   id: string;
+  tags: string[];
   result: 'Victory' | 'Defeat' | 'Draw';
 };
 
@@ -126,7 +128,9 @@ export const replaysParser = async (data: main.WarnoData[]): Promise<ParserResul
       duration: parseInt(replay.warno.result.Duration),
       mapKey: replay.warno.game.Map,
       map: typedMaps[replay.warno.game.Map] || replay.warno.game.Map,
+      version: replay.warno.game.Version,
       id: replay.warno.game.UniqueSessionId,
+      tags: replay.tags || [],
       result
     };
 
@@ -152,7 +156,7 @@ export const replaysParser = async (data: main.WarnoData[]): Promise<ParserResul
         enemyRank: replay.warno.players[enemyKey].PlayerRank,
         enemyDeck: replay.warno.players?.[enemyKey]?.PlayerDeckContent,
         enemyElo: replay.warno.players?.[enemyKey]?.PlayerElo,
-        enemySteamId: replay.warno.players?.[enemyKey]?.PlayerAvatar.split('/').pop(),
+        enemySteamId: replay.warno.players?.[enemyKey]?.PlayerAvatar.split('?')[0].split('/').pop(),
         eloChange: expectedEloChange(
           parseInt(replay.warno.players?.[playerKey].PlayerElo),
           parseInt(replay.warno.players?.[enemyKey].PlayerElo),
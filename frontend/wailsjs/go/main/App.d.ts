@@ -2,40 +2,130 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
-export function CreatePlayerNote(arg1:string,arg2:string):Promise<void>;
+export function AddReplayFolder(arg1:string,arg2:string):Promise<main.Settings>;
+
+export function ArchiveReplays(arg1:Array<string>,arg2:string):Promise<Array<main.ReplayFileChange>>;
+
+export function CancelStagedUpdate():Promise<void>;
+
+export function CheckForUpdate():Promise<main.UpdateStatus>;
+
+export function ClearRankedReplaysAnalyticsCache():Promise<void>;
+
+export function CloneSettingsProfile(arg1:string,arg2:string):Promise<main.Settings>;
+
+export function CreatePlayerNote(arg1:string,arg2:string,arg3:Array<string>,arg4:string):Promise<main.PlayerNote>;
+
+export function CreateSettingsProfile(arg1:string):Promise<main.Settings>;
+
+export function DeleteAppId():Promise<void>;
 
 export function DeletePlayerNote(arg1:string,arg2:string):Promise<void>;
 
+export function DeleteReplays(arg1:Array<string>):Promise<Array<main.ReplayFileChange>>;
+
+export function DeleteSettingsProfile(arg1:string):Promise<main.Settings>;
+
+export function DiagnoseReplays(arg1:Array<string>):Promise<main.ReplayDiagnosticsReport>;
+
+export function DownloadUpdate():Promise<main.UpdateStatus>;
+
+export function ExportPlayerNotes(arg1:main.NotesExportFilter):Promise<string>;
+
+export function ExportReplayBundle(arg1:Array<string>,arg2:string,arg3:boolean):Promise<string>;
+
 export function GetAppVersions():Promise<Array<string>>;
 
+export function GetCurrentSession(arg1:string):Promise<main.PlaySession>;
+
 export function GetDailyRecap(arg1:string):Promise<main.DailyRecap>;
 
-export function GetEugenPlayer(arg1:string):Promise<main.EugenPlayer>;
+export function GetEugenPlayer(arg1:string):Promise<main.EugenPlayerProfile>;
+
+export function GetEugenPlayerActivity(arg1:string):Promise<main.EugenPlayerActivity>;
+
+export function GetFavoritePlayersActivity():Promise<Array<main.EugenPlayerActivity>>;
 
 export function GetLeaderboard():Promise<Array<main.LeaderboardEntry>>;
 
+export function GetLeaderboardDiff(arg1:string,arg2:string):Promise<main.LeaderboardDiff>;
+
+export function GetLeaderboardPlayerTrajectory(arg1:number):Promise<Array<main.LeaderboardTrajectoryPoint>>;
+
+export function GetLeaderboardSnapshots():Promise<Array<main.LeaderboardSnapshotInfo>>;
+
+export function GetLocalIdentities():Promise<Array<main.PlayerIdentity>>;
+
+export function GetLocalRankedReplaysAnalytics(arg1:Array<string>,arg2:main.RankedReplaysAnalyticsFilter):Promise<main.RankedReplaysAnalyticsResponse>;
+
+export function GetPlaySessions(arg1:string):Promise<Array<main.PlaySession>>;
+
 export function GetPlayerGameHistory(arg1:string):Promise<Array<main.PlayerGame>>;
 
 export function GetPlayerIdsOptions():Promise<Array<main.PlayerIdsOption>>;
 
-export function GetPlayerNotes(arg1:string):Promise<string>;
+export function GetPlayerNotes(arg1:string):Promise<Array<main.PlayerNote>>;
+
+export function GetPlayerReplayHistory(arg1:string,arg2:Array<string>):Promise<Array<main.PlayerReplayHistoryEntry>>;
 
 export function GetPlayerReplays(arg1:string):Promise<Array<main.GetReplay>>;
 
-export function GetRankedReplaysAnalytics(arg1:number,arg2:number):Promise<main.RankedReplaysAnalyticsResponse>;
+export function GetRankedReplaysAnalytics(arg1:main.RankedReplaysAnalyticsFilter):Promise<main.RankedReplaysAnalyticsResponse>;
+
+export function GetReplayTags():Promise<Record<string, Array<string>>>;
 
 export function GetReplays(arg1:Array<string>):Promise<Array<main.WarnoData>>;
 
 export function GetSettings():Promise<main.Settings>;
 
+export function GetSettingsProfiles():Promise<Array<main.SettingsProfile>>;
+
 export function GetSteamPlayer(arg1:string):Promise<main.SteamPlayer>;
 
+export function GetSteamPlayers(arg1:Array<string>):Promise<Record<string, main.SteamPlayer>>;
+
+export function GetTeamPoolReplays():Promise<Array<main.PooledReplay>>;
+
+export function GetTeamPoolStats():Promise<main.TeamPoolStats>;
+
+export function GetTelemetryPayload():Promise<main.TelemetryInfo>;
+
 export function GetWarnoSaveFolders():Promise<string>;
 
+export function ImportPlayerNotes(arg1:string):Promise<main.NotesImportResult>;
+
+export function ImportReplayBundle():Promise<main.TeamPoolImportResult>;
+
+export function RegenerateAppId():Promise<string>;
+
+export function RemoveReplayFolder(arg1:string):Promise<main.Settings>;
+
+export function RemoveTeamPoolMember(arg1:string):Promise<void>;
+
+export function RenameReplays(arg1:Array<string>,arg2:string):Promise<Array<main.ReplayFileChange>>;
+
+export function ResolvePlayerIdentities(arg1:Array<string>):Promise<Record<string, main.PlayerIdentity>>;
+
 export function SaveSettings(arg1:main.Settings):Promise<void>;
 
+export function SearchPlayerIdentities(arg1:string):Promise<Array<main.PlayerIdentity>>;
+
 export function SearchPlayerInApi(arg1:string):Promise<Array<main.GetUser>>;
 
+export function SearchPlayerNotes(arg1:string):Promise<Array<main.PlayerNoteSearchResult>>;
+
+export function SelectArchiveFolder():Promise<string>;
+
+export function SelectReplayFolder():Promise<string>;
+
 export function SendPlayersToAPI(arg1:Array<main.PostUser>):Promise<Record<string, boolean>>;
 
 export function SendRankedReplaysToAPI(arg1:Array<main.RankedReplayInput>):Promise<Record<string, any>>;
+
+export function SetReplayTags(arg1:string,arg2:Array<string>):Promise<Array<string>>;
+
+export function SwitchSettingsProfile(arg1:string):Promise<main.Settings>;
+
+export function UpdatePlayerNote(arg1:string,arg2:string,arg3:string,arg4:Array<string>,arg5:string):Promise<main.PlayerNote>;
+
+export function UpdateReplayFolder(arg1:string,arg2:string,arg3:boolean):Promise<main.Settings>;
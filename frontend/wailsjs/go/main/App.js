@@ -2,18 +2,78 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
-export function CreatePlayerNote(arg1, arg2) {
-  return window['go']['main']['App']['CreatePlayerNote'](arg1, arg2);
+export function AddReplayFolder(arg1, arg2) {
+  return window['go']['main']['App']['AddReplayFolder'](arg1, arg2);
+}
+
+export function ArchiveReplays(arg1, arg2) {
+  return window['go']['main']['App']['ArchiveReplays'](arg1, arg2);
+}
+
+export function CancelStagedUpdate() {
+  return window['go']['main']['App']['CancelStagedUpdate']();
+}
+
+export function CheckForUpdate() {
+  return window['go']['main']['App']['CheckForUpdate']();
+}
+
+export function ClearRankedReplaysAnalyticsCache() {
+  return window['go']['main']['App']['ClearRankedReplaysAnalyticsCache']();
+}
+
+export function CloneSettingsProfile(arg1, arg2) {
+  return window['go']['main']['App']['CloneSettingsProfile'](arg1, arg2);
+}
+
+export function CreatePlayerNote(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CreatePlayerNote'](arg1, arg2, arg3, arg4);
+}
+
+export function CreateSettingsProfile(arg1) {
+  return window['go']['main']['App']['CreateSettingsProfile'](arg1);
+}
+
+export function DeleteAppId() {
+  return window['go']['main']['App']['DeleteAppId']();
 }
 
 export function DeletePlayerNote(arg1, arg2) {
   return window['go']['main']['App']['DeletePlayerNote'](arg1, arg2);
 }
 
+export function DeleteReplays(arg1) {
+  return window['go']['main']['App']['DeleteReplays'](arg1);
+}
+
+export function DeleteSettingsProfile(arg1) {
+  return window['go']['main']['App']['DeleteSettingsProfile'](arg1);
+}
+
+export function DiagnoseReplays(arg1) {
+  return window['go']['main']['App']['DiagnoseReplays'](arg1);
+}
+
+export function DownloadUpdate() {
+  return window['go']['main']['App']['DownloadUpdate']();
+}
+
+export function ExportPlayerNotes(arg1) {
+  return window['go']['main']['App']['ExportPlayerNotes'](arg1);
+}
+
+export function ExportReplayBundle(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportReplayBundle'](arg1, arg2, arg3);
+}
+
 export function GetAppVersions() {
   return window['go']['main']['App']['GetAppVersions']();
 }
 
+export function GetCurrentSession(arg1) {
+  return window['go']['main']['App']['GetCurrentSession'](arg1);
+}
+
 export function GetDailyRecap(arg1) {
   return window['go']['main']['App']['GetDailyRecap'](arg1);
 }
@@ -22,10 +82,42 @@ export function GetEugenPlayer(arg1) {
   return window['go']['main']['App']['GetEugenPlayer'](arg1);
 }
 
+export function GetEugenPlayerActivity(arg1) {
+  return window['go']['main']['App']['GetEugenPlayerActivity'](arg1);
+}
+
+export function GetFavoritePlayersActivity() {
+  return window['go']['main']['App']['GetFavoritePlayersActivity']();
+}
+
 export function GetLeaderboard() {
   return window['go']['main']['App']['GetLeaderboard']();
 }
 
+export function GetLeaderboardDiff(arg1, arg2) {
+  return window['go']['main']['App']['GetLeaderboardDiff'](arg1, arg2);
+}
+
+export function GetLeaderboardPlayerTrajectory(arg1) {
+  return window['go']['main']['App']['GetLeaderboardPlayerTrajectory'](arg1);
+}
+
+export function GetLeaderboardSnapshots() {
+  return window['go']['main']['App']['GetLeaderboardSnapshots']();
+}
+
+export function GetLocalIdentities() {
+  return window['go']['main']['App']['GetLocalIdentities']();
+}
+
+export function GetLocalRankedReplaysAnalytics(arg1, arg2) {
+  return window['go']['main']['App']['GetLocalRankedReplaysAnalytics'](arg1, arg2);
+}
+
+export function GetPlaySessions(arg1) {
+  return window['go']['main']['App']['GetPlaySessions'](arg1);
+}
+
 export function GetPlayerGameHistory(arg1) {
   return window['go']['main']['App']['GetPlayerGameHistory'](arg1);
 }
@@ -38,12 +130,20 @@ export function GetPlayerNotes(arg1) {
   return window['go']['main']['App']['GetPlayerNotes'](arg1);
 }
 
+export function GetPlayerReplayHistory(arg1, arg2) {
+  return window['go']['main']['App']['GetPlayerReplayHistory'](arg1, arg2);
+}
+
 export function GetPlayerReplays(arg1) {
   return window['go']['main']['App']['GetPlayerReplays'](arg1);
 }
 
-export function GetRankedReplaysAnalytics(arg1, arg2) {
-  return window['go']['main']['App']['GetRankedReplaysAnalytics'](arg1, arg2);
+export function GetRankedReplaysAnalytics(arg1) {
+  return window['go']['main']['App']['GetRankedReplaysAnalytics'](arg1);
+}
+
+export function GetReplayTags() {
+  return window['go']['main']['App']['GetReplayTags']();
 }
 
 export function GetReplays(arg1) {
@@ -54,22 +154,86 @@ export function GetSettings() {
   return window['go']['main']['App']['GetSettings']();
 }
 
+export function GetSettingsProfiles() {
+  return window['go']['main']['App']['GetSettingsProfiles']();
+}
+
 export function GetSteamPlayer(arg1) {
   return window['go']['main']['App']['GetSteamPlayer'](arg1);
 }
 
+export function GetSteamPlayers(arg1) {
+  return window['go']['main']['App']['GetSteamPlayers'](arg1);
+}
+
+export function GetTeamPoolReplays() {
+  return window['go']['main']['App']['GetTeamPoolReplays']();
+}
+
+export function GetTeamPoolStats() {
+  return window['go']['main']['App']['GetTeamPoolStats']();
+}
+
+export function GetTelemetryPayload() {
+  return window['go']['main']['App']['GetTelemetryPayload']();
+}
+
 export function GetWarnoSaveFolders() {
   return window['go']['main']['App']['GetWarnoSaveFolders']();
 }
 
+export function ImportPlayerNotes(arg1) {
+  return window['go']['main']['App']['ImportPlayerNotes'](arg1);
+}
+
+export function ImportReplayBundle() {
+  return window['go']['main']['App']['ImportReplayBundle']();
+}
+
+export function RegenerateAppId() {
+  return window['go']['main']['App']['RegenerateAppId']();
+}
+
+export function RemoveReplayFolder(arg1) {
+  return window['go']['main']['App']['RemoveReplayFolder'](arg1);
+}
+
+export function RemoveTeamPoolMember(arg1) {
+  return window['go']['main']['App']['RemoveTeamPoolMember'](arg1);
+}
+
+export function RenameReplays(arg1, arg2) {
+  return window['go']['main']['App']['RenameReplays'](arg1, arg2);
+}
+
+export function ResolvePlayerIdentities(arg1) {
+  return window['go']['main']['App']['ResolvePlayerIdentities'](arg1);
+}
+
 export function SaveSettings(arg1) {
   return window['go']['main']['App']['SaveSettings'](arg1);
 }
 
+export function SearchPlayerIdentities(arg1) {
+  return window['go']['main']['App']['SearchPlayerIdentities'](arg1);
+}
+
 export function SearchPlayerInApi(arg1) {
   return window['go']['main']['App']['SearchPlayerInApi'](arg1);
 }
 
+export function SearchPlayerNotes(arg1) {
+  return window['go']['main']['App']['SearchPlayerNotes'](arg1);
+}
+
+export function SelectArchiveFolder() {
+  return window['go']['main']['App']['SelectArchiveFolder']();
+}
+
+export function SelectReplayFolder() {
+  return window['go']['main']['App']['SelectReplayFolder']();
+}
+
 export function SendPlayersToAPI(arg1) {
   return window['go']['main']['App']['SendPlayersToAPI'](arg1);
 }
@@ -77,3 +241,19 @@ export function SendPlayersToAPI(arg1) {
 export function SendRankedReplaysToAPI(arg1) {
   return window['go']['main']['App']['SendRankedReplaysToAPI'](arg1);
 }
+
+export function SetReplayTags(arg1, arg2) {
+  return window['go']['main']['App']['SetReplayTags'](arg1, arg2);
+}
+
+export function SwitchSettingsProfile(arg1) {
+  return window['go']['main']['App']['SwitchSettingsProfile'](arg1);
+}
+
+export function UpdatePlayerNote(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['UpdatePlayerNote'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function UpdateReplayFolder(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UpdateReplayFolder'](arg1, arg2, arg3);
+}
@@ -1,10 +1,27 @@
 export namespace main {
 	
+	export class AnalyticsPayload {
+	    app_id: string;
+	    version: string;
+	    event: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnalyticsPayload(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.app_id = source["app_id"];
+	        this.version = source["version"];
+	        this.event = source["event"];
+	    }
+	}
 	export class DailyRecap {
 	    eloChange: number;
 	    gamesPlayed: number;
 	    wins: number;
 	    losses: number;
+	    draws: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new DailyRecap(source);
@@ -16,6 +33,7 @@ export namespace main {
 	        this.gamesPlayed = source["gamesPlayed"];
 	        this.wins = source["wins"];
 	        this.losses = source["losses"];
+	        this.draws = source["draws"];
 	    }
 	}
 	export class DivisionWinrateRow {
@@ -26,6 +44,10 @@ export namespace main {
 	    losses: number;
 	    draws: number;
 	    winRate: number;
+	    winRateLow: number;
+	    winRateHigh: number;
+	    drawAdjustedWinRate: number;
+	    confidence: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new DivisionWinrateRow(source);
@@ -40,6 +62,10 @@ export namespace main {
 	        this.losses = source["losses"];
 	        this.draws = source["draws"];
 	        this.winRate = source["winRate"];
+	        this.winRateLow = source["winRateLow"];
+	        this.winRateHigh = source["winRateHigh"];
+	        this.drawAdjustedWinRate = source["drawAdjustedWinRate"];
+	        this.confidence = source["confidence"];
 	    }
 	}
 	export class DivisionOnMapGroup {
@@ -82,6 +108,10 @@ export namespace main {
 	    losses: number;
 	    draws: number;
 	    winRate: number;
+	    winRateLow: number;
+	    winRateHigh: number;
+	    drawAdjustedWinRate: number;
+	    confidence: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new DivisionOnMapVsOpponentStats(source);
@@ -96,6 +126,10 @@ export namespace main {
 	        this.losses = source["losses"];
 	        this.draws = source["draws"];
 	        this.winRate = source["winRate"];
+	        this.winRateLow = source["winRateLow"];
+	        this.winRateHigh = source["winRateHigh"];
+	        this.drawAdjustedWinRate = source["drawAdjustedWinRate"];
+	        this.confidence = source["confidence"];
 	    }
 	}
 	export class DivisionOnMapVsDivisionGroup {
@@ -171,6 +205,10 @@ export namespace main {
 	    losses: number;
 	    draws: number;
 	    winRate: number;
+	    winRateLow: number;
+	    winRateHigh: number;
+	    drawAdjustedWinRate: number;
+	    confidence: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new DivisionVsOpponentStats(source);
@@ -185,6 +223,10 @@ export namespace main {
 	        this.losses = source["losses"];
 	        this.draws = source["draws"];
 	        this.winRate = source["winRate"];
+	        this.winRateLow = source["winRateLow"];
+	        this.winRateHigh = source["winRateHigh"];
+	        this.drawAdjustedWinRate = source["drawAdjustedWinRate"];
+	        this.confidence = source["confidence"];
 	    }
 	}
 	export class DivisionVsGroup {
@@ -221,142 +263,208 @@ export namespace main {
 	}
 	
 	
-	export class EugenPlayer {
-	    _id: string;
-	    _rev: string;
-	    "@time_challenge_played": string;
-	    "@nb_dca_bought": string;
-	    "@nb_art_bought": string;
-	    ranked_nation_0: string;
-	    "@campaign_nato": string;
-	    "@campaign_wins": string;
-	    "@campaign_last_game": string;
-	    "@time_campaign_played": string;
-	    "@multi_pact": string;
-	    "@multi_loss": string;
-	    "@xp_skirmish": string;
-	    "@deployment_breakthrough": string;
-	    "@nb_tank_bought": string;
-	    "@time_tutorial_played": string;
-	    "@nb_reco_bought": string;
-	    ELO: string;
-	    ranked_win: string;
-	    ranked_nation_1: string;
-	    ranked_last_game: string;
-	    ELO_LB_delta_value: string;
-	    ELO_LB_delta_rank: string;
-	    ELO_LB_value: string;
-	    ranked_loss: string;
-	    ELO_LB_rank: string;
-	    "@skirmish_played": string;
-	    "@deployment_closequarter_conquest": string;
-	    "@multi_draw": string;
-	    "@skirmish_win": string;
-	    "@skirmish_loss": string;
-	    "@skirmish_last_game": string;
-	    "@time_armory_played": string;
-	    "@deployment_conquest": string;
-	    "@skirmish_nato": string;
-	    "@level": string;
-	    "@total_unit_bought": string;
-	    "@skirmish_win_ai_6": string;
-	    "@xp_ranked": string;
-	    "@multi_win": string;
-	    "@time_skirmish_played": string;
-	    "@skirmish_win_ai_5": string;
-	    "@skirmish_win_ai_4": string;
-	    "@skirmish_win_ai_3": string;
-	    "@skirmish_win_ai_2": string;
-	    "@skirmish_draw": string;
-	    "@xp_multi": string;
-	    "@nb_air_bought": string;
-	    "@nb_sup_bought": string;
-	    "@skirmish_pact": string;
-	    "@multi_last_game": string;
-	    "@time_ranked_played": string;
-	    "@time_menu_played": string;
-	    ranked_foul: string;
-	    "@deployment_strategic": string;
-	    "@multi_played": string;
-	    "@time_multi_played": string;
-	    "@multi_nato": string;
-	    "@nb_inf_bought": string;
-	    "@time_replay_played": string;
-	    "@nb_at_bought": string;
-	    "@xp_campaign": string;
-	    "@time_strategic_played": string;
-	
-	    static createFrom(source: any = {}) {
-	        return new EugenPlayer(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this._id = source["_id"];
-	        this._rev = source["_rev"];
-	        this["@time_challenge_played"] = source["@time_challenge_played"];
-	        this["@nb_dca_bought"] = source["@nb_dca_bought"];
-	        this["@nb_art_bought"] = source["@nb_art_bought"];
-	        this.ranked_nation_0 = source["ranked_nation_0"];
-	        this["@campaign_nato"] = source["@campaign_nato"];
-	        this["@campaign_wins"] = source["@campaign_wins"];
-	        this["@campaign_last_game"] = source["@campaign_last_game"];
-	        this["@time_campaign_played"] = source["@time_campaign_played"];
-	        this["@multi_pact"] = source["@multi_pact"];
-	        this["@multi_loss"] = source["@multi_loss"];
-	        this["@xp_skirmish"] = source["@xp_skirmish"];
-	        this["@deployment_breakthrough"] = source["@deployment_breakthrough"];
-	        this["@nb_tank_bought"] = source["@nb_tank_bought"];
-	        this["@time_tutorial_played"] = source["@time_tutorial_played"];
-	        this["@nb_reco_bought"] = source["@nb_reco_bought"];
-	        this.ELO = source["ELO"];
-	        this.ranked_win = source["ranked_win"];
-	        this.ranked_nation_1 = source["ranked_nation_1"];
-	        this.ranked_last_game = source["ranked_last_game"];
-	        this.ELO_LB_delta_value = source["ELO_LB_delta_value"];
-	        this.ELO_LB_delta_rank = source["ELO_LB_delta_rank"];
-	        this.ELO_LB_value = source["ELO_LB_value"];
-	        this.ranked_loss = source["ranked_loss"];
-	        this.ELO_LB_rank = source["ELO_LB_rank"];
-	        this["@skirmish_played"] = source["@skirmish_played"];
-	        this["@deployment_closequarter_conquest"] = source["@deployment_closequarter_conquest"];
-	        this["@multi_draw"] = source["@multi_draw"];
-	        this["@skirmish_win"] = source["@skirmish_win"];
-	        this["@skirmish_loss"] = source["@skirmish_loss"];
-	        this["@skirmish_last_game"] = source["@skirmish_last_game"];
-	        this["@time_armory_played"] = source["@time_armory_played"];
-	        this["@deployment_conquest"] = source["@deployment_conquest"];
-	        this["@skirmish_nato"] = source["@skirmish_nato"];
-	        this["@level"] = source["@level"];
-	        this["@total_unit_bought"] = source["@total_unit_bought"];
-	        this["@skirmish_win_ai_6"] = source["@skirmish_win_ai_6"];
-	        this["@xp_ranked"] = source["@xp_ranked"];
-	        this["@multi_win"] = source["@multi_win"];
-	        this["@time_skirmish_played"] = source["@time_skirmish_played"];
-	        this["@skirmish_win_ai_5"] = source["@skirmish_win_ai_5"];
-	        this["@skirmish_win_ai_4"] = source["@skirmish_win_ai_4"];
-	        this["@skirmish_win_ai_3"] = source["@skirmish_win_ai_3"];
-	        this["@skirmish_win_ai_2"] = source["@skirmish_win_ai_2"];
-	        this["@skirmish_draw"] = source["@skirmish_draw"];
-	        this["@xp_multi"] = source["@xp_multi"];
-	        this["@nb_air_bought"] = source["@nb_air_bought"];
-	        this["@nb_sup_bought"] = source["@nb_sup_bought"];
-	        this["@skirmish_pact"] = source["@skirmish_pact"];
-	        this["@multi_last_game"] = source["@multi_last_game"];
-	        this["@time_ranked_played"] = source["@time_ranked_played"];
-	        this["@time_menu_played"] = source["@time_menu_played"];
-	        this.ranked_foul = source["ranked_foul"];
-	        this["@deployment_strategic"] = source["@deployment_strategic"];
-	        this["@multi_played"] = source["@multi_played"];
-	        this["@time_multi_played"] = source["@time_multi_played"];
-	        this["@multi_nato"] = source["@multi_nato"];
-	        this["@nb_inf_bought"] = source["@nb_inf_bought"];
-	        this["@time_replay_played"] = source["@time_replay_played"];
-	        this["@nb_at_bought"] = source["@nb_at_bought"];
-	        this["@xp_campaign"] = source["@xp_campaign"];
-	        this["@time_strategic_played"] = source["@time_strategic_played"];
+	export class EugenModeRecord {
+	    played: number;
+	    wins: number;
+	    losses: number;
+	    draws: number;
+	    fouls: number;
+	    winRate: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EugenModeRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.played = source["played"];
+	        this.wins = source["wins"];
+	        this.losses = source["losses"];
+	        this.draws = source["draws"];
+	        this.fouls = source["fouls"];
+	        this.winRate = source["winRate"];
+	    }
+	}
+	export class EugenModeTime {
+	    mode: string;
+	    seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EugenModeTime(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.seconds = source["seconds"];
+	    }
+	}
+	export class EugenUnitMixChange {
+	    category: string;
+	    bought: number;
+	    recentPercent: number;
+	    overallPercent: number;
+	    change: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EugenUnitMixChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.category = source["category"];
+	        this.bought = source["bought"];
+	        this.recentPercent = source["recentPercent"];
+	        this.overallPercent = source["overallPercent"];
+	        this.change = source["change"];
+	    }
+	}
+	export class EugenPlayerActivity {
+	    playerId: string;
+	    // Go type: time
+	    since: any;
+	    // Go type: time
+	    until: any;
+	    gamesPlayed: number;
+	    rankedGames: number;
+	    rankedWins: number;
+	    rankedLosses: number;
+	    rankedFouls: number;
+	    eloChange: number;
+	    rankChange: number;
+	    unitsBought: number;
+	    unitMixChange: EugenUnitMixChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EugenPlayerActivity(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.playerId = source["playerId"];
+	        this.since = this.convertValues(source["since"], null);
+	        this.until = this.convertValues(source["until"], null);
+	        this.gamesPlayed = source["gamesPlayed"];
+	        this.rankedGames = source["rankedGames"];
+	        this.rankedWins = source["rankedWins"];
+	        this.rankedLosses = source["rankedLosses"];
+	        this.rankedFouls = source["rankedFouls"];
+	        this.eloChange = source["eloChange"];
+	        this.rankChange = source["rankChange"];
+	        this.unitsBought = source["unitsBought"];
+	        this.unitMixChange = this.convertValues(source["unitMixChange"], EugenUnitMixChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EugenUnitPurchase {
+	    category: string;
+	    count: number;
+	    percent: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EugenUnitPurchase(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.category = source["category"];
+	        this.count = source["count"];
+	        this.percent = source["percent"];
+	    }
+	}
+	export class EugenPlayerProfile {
+	    id: string;
+	    level: number;
+	    elo: number;
+	    leaderboardElo: number;
+	    leaderboardEloDelta: number;
+	    leaderboardRank: number;
+	    leaderboardRankDelta: number;
+	    ranked: EugenModeRecord;
+	    multi: EugenModeRecord;
+	    skirmish: EugenModeRecord;
+	    campaignWins: number;
+	    xpRanked: number;
+	    xpMulti: number;
+	    xpSkirmish: number;
+	    xpCampaign: number;
+	    totalUnitsBought: number;
+	    unitPurchases: EugenUnitPurchase[];
+	    timePlayed: EugenModeTime[];
+	    totalTimePlayedSeconds: number;
+	    natoGames: number;
+	    pactGames: number;
+	    natoShare: number;
+	    preferredSide: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EugenPlayerProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.level = source["level"];
+	        this.elo = source["elo"];
+	        this.leaderboardElo = source["leaderboardElo"];
+	        this.leaderboardEloDelta = source["leaderboardEloDelta"];
+	        this.leaderboardRank = source["leaderboardRank"];
+	        this.leaderboardRankDelta = source["leaderboardRankDelta"];
+	        this.ranked = this.convertValues(source["ranked"], EugenModeRecord);
+	        this.multi = this.convertValues(source["multi"], EugenModeRecord);
+	        this.skirmish = this.convertValues(source["skirmish"], EugenModeRecord);
+	        this.campaignWins = source["campaignWins"];
+	        this.xpRanked = source["xpRanked"];
+	        this.xpMulti = source["xpMulti"];
+	        this.xpSkirmish = source["xpSkirmish"];
+	        this.xpCampaign = source["xpCampaign"];
+	        this.totalUnitsBought = source["totalUnitsBought"];
+	        this.unitPurchases = this.convertValues(source["unitPurchases"], EugenUnitPurchase);
+	        this.timePlayed = this.convertValues(source["timePlayed"], EugenModeTime);
+	        this.totalTimePlayedSeconds = source["totalTimePlayedSeconds"];
+	        this.natoGames = source["natoGames"];
+	        this.pactGames = source["pactGames"];
+	        this.natoShare = source["natoShare"];
+	        this.preferredSide = source["preferredSide"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
+	
 	export class Game {
 	    CombatRule: string;
 	    DeploymentMode: string;
@@ -490,119 +598,103 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class LeaderboardEntry {
-	    id: number;
-	    elo: number;
+	export class IdentityName {
 	    name: string;
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	    games: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new LeaderboardEntry(source);
+	        return new IdentityName(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.elo = source["elo"];
 	        this.name = source["name"];
-	    }
-	}
-	export class Player {
-	    PlayerAlliance: string;
-	    PlayerAvatar: string;
-	    PlayerDeckContent: string;
-	    PlayerElo: string;
-	    PlayerIALevel: string;
-	    PlayerIncomeRate: string;
-	    PlayerIsEnteredInLobby: string;
-	    PlayerLevel: string;
-	    PlayerName: string;
-	    PlayerRank: string;
-	    PlayerReady: string;
-	    PlayerScoreLimit: string;
-	    PlayerSkinIndexUsed: string;
-	    PlayerUserId: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new Player(source);
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	        this.games = source["games"];
 	    }
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.PlayerAlliance = source["PlayerAlliance"];
-	        this.PlayerAvatar = source["PlayerAvatar"];
-	        this.PlayerDeckContent = source["PlayerDeckContent"];
-	        this.PlayerElo = source["PlayerElo"];
-	        this.PlayerIALevel = source["PlayerIALevel"];
-	        this.PlayerIncomeRate = source["PlayerIncomeRate"];
-	        this.PlayerIsEnteredInLobby = source["PlayerIsEnteredInLobby"];
-	        this.PlayerLevel = source["PlayerLevel"];
-	        this.PlayerName = source["PlayerName"];
-	        this.PlayerRank = source["PlayerRank"];
-	        this.PlayerReady = source["PlayerReady"];
-	        this.PlayerScoreLimit = source["PlayerScoreLimit"];
-	        this.PlayerSkinIndexUsed = source["PlayerSkinIndexUsed"];
-	        this.PlayerUserId = source["PlayerUserId"];
-	    }
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class PlayerGame {
-	    gameId: string;
-	    score: string;
-	    date: string;
-	    playerName: string;
-	    enemyName: string;
-	    playerElo: string[];
-	    enemyElo: string[];
-	    playerEloChange: string;
-	    enemyEloChange: string;
-	    result: string;
+	export class LeaderboardEntry {
+	    id: number;
+	    rank: number;
+	    elo: number;
+	    name: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new PlayerGame(source);
+	        return new LeaderboardEntry(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.gameId = source["gameId"];
-	        this.score = source["score"];
-	        this.date = source["date"];
-	        this.playerName = source["playerName"];
-	        this.enemyName = source["enemyName"];
-	        this.playerElo = source["playerElo"];
-	        this.enemyElo = source["enemyElo"];
-	        this.playerEloChange = source["playerEloChange"];
-	        this.enemyEloChange = source["enemyEloChange"];
-	        this.result = source["result"];
+	        this.id = source["id"];
+	        this.rank = source["rank"];
+	        this.elo = source["elo"];
+	        this.name = source["name"];
 	    }
 	}
-	export class PlayerIdsOption {
-	    label: string;
-	    value: string;
+	export class LeaderboardMovement {
+	    id: number;
+	    name: string;
+	    previousRank: number;
+	    rank: number;
+	    rankChange: number;
+	    previousElo: number;
+	    elo: number;
+	    eloChange: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new PlayerIdsOption(source);
+	        return new LeaderboardMovement(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.label = source["label"];
-	        this.value = source["value"];
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.previousRank = source["previousRank"];
+	        this.rank = source["rank"];
+	        this.rankChange = source["rankChange"];
+	        this.previousElo = source["previousElo"];
+	        this.elo = source["elo"];
+	        this.eloChange = source["eloChange"];
 	    }
 	}
-	export class PostReplay {
-	    division: string;
-	    eugenId: string;
+	export class LeaderboardSnapshotInfo {
+	    id: string;
 	    // Go type: time
-	    createdAt: any;
+	    takenAt: any;
+	    players: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new PostReplay(source);
+	        return new LeaderboardSnapshotInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.division = source["division"];
-	        this.eugenId = source["eugenId"];
-	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.id = source["id"];
+	        this.takenAt = this.convertValues(source["takenAt"], null);
+	        this.players = source["players"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -623,32 +715,24 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class PostUser {
-	    usernames: string[];
-	    ranks: number[];
-	    eugenId: number;
-	    steamId: string;
-	    lastKnownRank: number;
-	    // Go type: time
-	    lastKnownRankCreatedAt: any;
-	    // Go type: time
-	    oldestReplayCreatedAt: any;
-	    replays?: PostReplay[];
+	export class LeaderboardDiff {
+	    from: LeaderboardSnapshotInfo;
+	    to: LeaderboardSnapshotInfo;
+	    movements: LeaderboardMovement[];
+	    newEntrants: LeaderboardEntry[];
+	    dropouts: LeaderboardEntry[];
 	
 	    static createFrom(source: any = {}) {
-	        return new PostUser(source);
+	        return new LeaderboardDiff(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.usernames = source["usernames"];
-	        this.ranks = source["ranks"];
-	        this.eugenId = source["eugenId"];
-	        this.steamId = source["steamId"];
-	        this.lastKnownRank = source["lastKnownRank"];
-	        this.lastKnownRankCreatedAt = this.convertValues(source["lastKnownRankCreatedAt"], null);
-	        this.oldestReplayCreatedAt = this.convertValues(source["oldestReplayCreatedAt"], null);
-	        this.replays = this.convertValues(source["replays"], PostReplay);
+	        this.from = this.convertValues(source["from"], LeaderboardSnapshotInfo);
+	        this.to = this.convertValues(source["to"], LeaderboardSnapshotInfo);
+	        this.movements = this.convertValues(source["movements"], LeaderboardMovement);
+	        this.newEntrants = this.convertValues(source["newEntrants"], LeaderboardEntry);
+	        this.dropouts = this.convertValues(source["dropouts"], LeaderboardEntry);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -669,19 +753,778 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class RankedReplayInput {
-	    eugenId?: string;
-	    player1EugenId: number;
-	    player2EugenId: number;
-	    player1Elo?: number;
-	    player1Rank?: number;
-	    player2Elo?: number;
-	    player2Rank?: number;
+	
+	
+	
+	export class LeaderboardTrajectoryPoint {
+	    snapshotId: string;
+	    // Go type: time
+	    takenAt: any;
+	    rank: number;
+	    elo: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LeaderboardTrajectoryPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.snapshotId = source["snapshotId"];
+	        this.takenAt = this.convertValues(source["takenAt"], null);
+	        this.rank = source["rank"];
+	        this.elo = source["elo"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PoolStats {
+	    games: number;
+	    wins: number;
+	    losses: number;
+	    draws: number;
+	    winRate: number;
+	    averageDuration: number;
+	    firstGameAt?: string;
+	    lastGameAt?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PoolStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.games = source["games"];
+	        this.wins = source["wins"];
+	        this.losses = source["losses"];
+	        this.draws = source["draws"];
+	        this.winRate = source["winRate"];
+	        this.averageDuration = source["averageDuration"];
+	        this.firstGameAt = source["firstGameAt"];
+	        this.lastGameAt = source["lastGameAt"];
+	    }
+	}
+	export class MemberPoolStats {
+	    member: string;
+	    eugenIds: string[];
+	    stats: PoolStats;
+	
+	    static createFrom(source: any = {}) {
+	        return new MemberPoolStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.member = source["member"];
+	        this.eugenIds = source["eugenIds"];
+	        this.stats = this.convertValues(source["stats"], PoolStats);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NotesExportFilter {
+	    playerIds?: string[];
+	    tags?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new NotesExportFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.playerIds = source["playerIds"];
+	        this.tags = source["tags"];
+	    }
+	}
+	export class NotesImportResult {
+	    players: number;
+	    added: number;
+	    updated: number;
+	    copied: number;
+	    unchanged: number;
+	    keptLocal: number;
+	    favoritesAdded: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotesImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.players = source["players"];
+	        this.added = source["added"];
+	        this.updated = source["updated"];
+	        this.copied = source["copied"];
+	        this.unchanged = source["unchanged"];
+	        this.keptLocal = source["keptLocal"];
+	        this.favoritesAdded = source["favoritesAdded"];
+	    }
+	}
+	export class SessionTilt {
+	    level: string;
+	    losingStreak: number;
+	    eloLost: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionTilt(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.level = source["level"];
+	        this.losingStreak = source["losingStreak"];
+	        this.eloLost = source["eloLost"];
+	    }
+	}
+	export class SessionMatchup {
+	    divisionId: number;
+	    division: string;
+	    enemyDivisionId: number;
+	    enemyDivision: string;
+	    deck: string;
+	    enemyDeck: string;
+	    games: number;
+	    wins: number;
+	    losses: number;
+	    winRate: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionMatchup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.divisionId = source["divisionId"];
+	        this.division = source["division"];
+	        this.enemyDivisionId = source["enemyDivisionId"];
+	        this.enemyDivision = source["enemyDivision"];
+	        this.deck = source["deck"];
+	        this.enemyDeck = source["enemyDeck"];
+	        this.games = source["games"];
+	        this.wins = source["wins"];
+	        this.losses = source["losses"];
+	        this.winRate = source["winRate"];
+	    }
+	}
+	export class SessionDeck {
+	    divisionId: number;
+	    division: string;
+	    deck: string;
+	    games: number;
+	    wins: number;
+	    losses: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionDeck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.divisionId = source["divisionId"];
+	        this.division = source["division"];
+	        this.deck = source["deck"];
+	        this.games = source["games"];
+	        this.wins = source["wins"];
+	        this.losses = source["losses"];
+	    }
+	}
+	export class SessionGame {
+	    gameId: string;
+	    playedAt: string;
+	    result: string;
+	    streak: number;
+	    elo: number;
+	    eloChange: number;
+	    deck: string;
+	    enemyDeck: string;
+	    map: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionGame(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.gameId = source["gameId"];
+	        this.playedAt = source["playedAt"];
+	        this.result = source["result"];
+	        this.streak = source["streak"];
+	        this.elo = source["elo"];
+	        this.eloChange = source["eloChange"];
+	        this.deck = source["deck"];
+	        this.enemyDeck = source["enemyDeck"];
+	        this.map = source["map"];
+	    }
+	}
+	export class PlaySession {
+	    id: string;
+	    playerId: string;
+	    startedAt: string;
+	    endedAt: string;
+	    active: boolean;
+	    gamesPlayed: number;
+	    wins: number;
+	    losses: number;
+	    draws: number;
+	    eloStart: number;
+	    eloEnd: number;
+	    eloChange: number;
+	    averageDurationSeconds: number;
+	    games: SessionGame[];
+	    decks: SessionDeck[];
+	    bestMatchup?: SessionMatchup;
+	    worstMatchup?: SessionMatchup;
+	    tilt: SessionTilt;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlaySession(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.playerId = source["playerId"];
+	        this.startedAt = source["startedAt"];
+	        this.endedAt = source["endedAt"];
+	        this.active = source["active"];
+	        this.gamesPlayed = source["gamesPlayed"];
+	        this.wins = source["wins"];
+	        this.losses = source["losses"];
+	        this.draws = source["draws"];
+	        this.eloStart = source["eloStart"];
+	        this.eloEnd = source["eloEnd"];
+	        this.eloChange = source["eloChange"];
+	        this.averageDurationSeconds = source["averageDurationSeconds"];
+	        this.games = this.convertValues(source["games"], SessionGame);
+	        this.decks = this.convertValues(source["decks"], SessionDeck);
+	        this.bestMatchup = this.convertValues(source["bestMatchup"], SessionMatchup);
+	        this.worstMatchup = this.convertValues(source["worstMatchup"], SessionMatchup);
+	        this.tilt = this.convertValues(source["tilt"], SessionTilt);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Player {
+	    PlayerAlliance: string;
+	    PlayerAvatar: string;
+	    PlayerDeckContent: string;
+	    PlayerElo: string;
+	    PlayerIALevel: string;
+	    PlayerIncomeRate: string;
+	    PlayerIsEnteredInLobby: string;
+	    PlayerLevel: string;
+	    PlayerName: string;
+	    PlayerRank: string;
+	    PlayerReady: string;
+	    PlayerScoreLimit: string;
+	    PlayerSkinIndexUsed: string;
+	    PlayerUserId: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Player(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.PlayerAlliance = source["PlayerAlliance"];
+	        this.PlayerAvatar = source["PlayerAvatar"];
+	        this.PlayerDeckContent = source["PlayerDeckContent"];
+	        this.PlayerElo = source["PlayerElo"];
+	        this.PlayerIALevel = source["PlayerIALevel"];
+	        this.PlayerIncomeRate = source["PlayerIncomeRate"];
+	        this.PlayerIsEnteredInLobby = source["PlayerIsEnteredInLobby"];
+	        this.PlayerLevel = source["PlayerLevel"];
+	        this.PlayerName = source["PlayerName"];
+	        this.PlayerRank = source["PlayerRank"];
+	        this.PlayerReady = source["PlayerReady"];
+	        this.PlayerScoreLimit = source["PlayerScoreLimit"];
+	        this.PlayerSkinIndexUsed = source["PlayerSkinIndexUsed"];
+	        this.PlayerUserId = source["PlayerUserId"];
+	    }
+	}
+	export class PlayerGame {
+	    gameId: string;
+	    score: string;
+	    date: string;
+	    playerName: string;
+	    enemyName: string;
+	    playerElo: string[];
+	    enemyElo: string[];
+	    playerEloChange: string;
+	    enemyEloChange: string;
+	    result: string;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlayerGame(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.gameId = source["gameId"];
+	        this.score = source["score"];
+	        this.date = source["date"];
+	        this.playerName = source["playerName"];
+	        this.enemyName = source["enemyName"];
+	        this.playerElo = source["playerElo"];
+	        this.enemyElo = source["enemyElo"];
+	        this.playerEloChange = source["playerEloChange"];
+	        this.enemyEloChange = source["enemyEloChange"];
+	        this.result = source["result"];
+	        this.source = source["source"];
+	    }
+	}
+	export class PlayerIdentity {
+	    eugenId: string;
+	    steamId?: string;
+	    isLocal: boolean;
+	    canonicalName: string;
+	    names: IdentityName[];
+	    folders?: string[];
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	    games: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlayerIdentity(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.eugenId = source["eugenId"];
+	        this.steamId = source["steamId"];
+	        this.isLocal = source["isLocal"];
+	        this.canonicalName = source["canonicalName"];
+	        this.names = this.convertValues(source["names"], IdentityName);
+	        this.folders = source["folders"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	        this.games = source["games"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PlayerIdsOption {
+	    label: string;
+	    value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlayerIdsOption(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.value = source["value"];
+	    }
+	}
+	export class PlayerNote {
+	    id: string;
+	    content: string;
+	    createdAt: string;
+	    updatedAt?: string;
+	    tags?: string[];
+	    replayId?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlayerNote(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.content = source["content"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	        this.tags = source["tags"];
+	        this.replayId = source["replayId"];
+	    }
+	}
+	export class PlayerNoteSearchResult {
+	    playerId: string;
+	    playerName?: string;
+	    note: PlayerNote;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlayerNoteSearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.playerId = source["playerId"];
+	        this.playerName = source["playerName"];
+	        this.note = this.convertValues(source["note"], PlayerNote);
+	        this.score = source["score"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Result {
+	    Duration: string;
+	    Victory: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Duration = source["Duration"];
+	        this.Victory = source["Victory"];
+	    }
+	}
+	export class Warno {
+	    game: Game;
+	    localPlayerEugenId: string;
+	    localPlayerKey: string;
+	    players: Record<string, Player>;
+	    playerCount: number;
+	    result: Result;
+	
+	    static createFrom(source: any = {}) {
+	        return new Warno(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.game = this.convertValues(source["game"], Game);
+	        this.localPlayerEugenId = source["localPlayerEugenId"];
+	        this.localPlayerKey = source["localPlayerKey"];
+	        this.players = this.convertValues(source["players"], Player, true);
+	        this.playerCount = source["playerCount"];
+	        this.result = this.convertValues(source["result"], Result);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WarnoData {
+	    fileName: string;
+	    filePath: string;
+	    key: string;
+	    createdAt: string;
+	    warno: Warno;
+	    tags?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WarnoData(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fileName = source["fileName"];
+	        this.filePath = source["filePath"];
+	        this.key = source["key"];
+	        this.createdAt = source["createdAt"];
+	        this.warno = this.convertValues(source["warno"], Warno);
+	        this.tags = source["tags"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PlayerReplayHistoryEntry {
+	    sessionId: string;
+	    remoteId?: number;
+	    division?: string;
+	    // Go type: time
+	    createdAt: any;
+	    source: string;
+	    replay?: WarnoData;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlayerReplayHistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.remoteId = source["remoteId"];
+	        this.division = source["division"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.source = source["source"];
+	        this.replay = this.convertValues(source["replay"], WarnoData);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PoolContributor {
+	    member: string;
+	    localPlayerKey: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PoolContributor(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.member = source["member"];
+	        this.localPlayerKey = source["localPlayerKey"];
+	    }
+	}
+	
+	export class PooledReplay {
+	    sessionId: string;
+	    member: string;
+	    contributors: PoolContributor[];
+	    // Go type: time
+	    importedAt: any;
+	    data: WarnoData;
+	
+	    static createFrom(source: any = {}) {
+	        return new PooledReplay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.member = source["member"];
+	        this.contributors = this.convertValues(source["contributors"], PoolContributor);
+	        this.importedAt = this.convertValues(source["importedAt"], null);
+	        this.data = this.convertValues(source["data"], WarnoData);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PostReplay {
+	    division: string;
+	    eugenId: string;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new PostReplay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.division = source["division"];
+	        this.eugenId = source["eugenId"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PostUser {
+	    usernames: string[];
+	    ranks: number[];
+	    eugenId: number;
+	    steamId: string;
+	    lastKnownRank: number;
+	    // Go type: time
+	    lastKnownRankCreatedAt: any;
+	    // Go type: time
+	    oldestReplayCreatedAt: any;
+	    replays?: PostReplay[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PostUser(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.usernames = source["usernames"];
+	        this.ranks = source["ranks"];
+	        this.eugenId = source["eugenId"];
+	        this.steamId = source["steamId"];
+	        this.lastKnownRank = source["lastKnownRank"];
+	        this.lastKnownRankCreatedAt = this.convertValues(source["lastKnownRankCreatedAt"], null);
+	        this.oldestReplayCreatedAt = this.convertValues(source["oldestReplayCreatedAt"], null);
+	        this.replays = this.convertValues(source["replays"], PostReplay);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RankedReplayInput {
+	    eugenId?: string;
+	    player1EugenId: number;
+	    player2EugenId: number;
+	    player1Elo?: number;
+	    player1Rank?: number;
+	    player2Elo?: number;
+	    player2Rank?: number;
 	    player1Name: string;
 	    player2Name: string;
 	    player1Division: number;
 	    player2Division: number;
 	    map: string;
+	    version?: string;
 	    duration: number;
 	    winnerPlayerEugenId?: number;
 	    submittedByEugenId: number;
@@ -706,6 +1549,7 @@ export namespace main {
 	        this.player1Division = source["player1Division"];
 	        this.player2Division = source["player2Division"];
 	        this.map = source["map"];
+	        this.version = source["version"];
 	        this.duration = source["duration"];
 	        this.winnerPlayerEugenId = source["winnerPlayerEugenId"];
 	        this.submittedByEugenId = source["submittedByEugenId"];
@@ -730,6 +1574,34 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class RankedReplaysAnalyticsFilter {
+	    maxRank: number;
+	    minElo: number;
+	    from?: string;
+	    to?: string;
+	    versions?: string[];
+	    maps?: string[];
+	    divisions?: number[];
+	    minDuration?: number;
+	    sortBy?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RankedReplaysAnalyticsFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxRank = source["maxRank"];
+	        this.minElo = source["minElo"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.versions = source["versions"];
+	        this.maps = source["maps"];
+	        this.divisions = source["divisions"];
+	        this.minDuration = source["minDuration"];
+	        this.sortBy = source["sortBy"];
+	    }
+	}
 	export class RankedReplaysAnalyticsResponse {
 	    totalGames: number;
 	    uniqueSubmitters: number;
@@ -770,26 +1642,241 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class Result {
-	    Duration: string;
-	    Victory: string;
+	export class RecoveredPlayer {
+	    name: string;
+	    eugenId: string;
+	    elo?: string;
+	    alliance: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Result(source);
+	        return new RecoveredPlayer(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.Duration = source["Duration"];
-	        this.Victory = source["Victory"];
+	        this.name = source["name"];
+	        this.eugenId = source["eugenId"];
+	        this.elo = source["elo"];
+	        this.alliance = source["alliance"];
+	    }
+	}
+	export class ReleaseInfo {
+	    version: string;
+	    name: string;
+	    notes: string;
+	    prerelease: boolean;
+	    publishedAt: string;
+	    url: string;
+	    assetName?: string;
+	    assetSize?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReleaseInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.name = source["name"];
+	        this.notes = source["notes"];
+	        this.prerelease = source["prerelease"];
+	        this.publishedAt = source["publishedAt"];
+	        this.url = source["url"];
+	        this.assetName = source["assetName"];
+	        this.assetSize = source["assetSize"];
+	    }
+	}
+	export class ReplayHeaderInfo {
+	    sessionId?: string;
+	    map?: string;
+	    gameMode?: string;
+	    version?: string;
+	    players: RecoveredPlayer[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplayHeaderInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.map = source["map"];
+	        this.gameMode = source["gameMode"];
+	        this.version = source["version"];
+	        this.players = this.convertValues(source["players"], RecoveredPlayer);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReplayDiagnostic {
+	    path: string;
+	    status: string;
+	    reason?: string;
+	    size: number;
+	    modifiedAt?: string;
+	    header?: ReplayHeaderInfo;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplayDiagnostic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.status = source["status"];
+	        this.reason = source["reason"];
+	        this.size = source["size"];
+	        this.modifiedAt = source["modifiedAt"];
+	        this.header = this.convertValues(source["header"], ReplayHeaderInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReplayDiagnosticsReport {
+	    generatedAt: string;
+	    folders: string[];
+	    counts: Record<string, number>;
+	    replays: ReplayDiagnostic[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplayDiagnosticsReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.generatedAt = source["generatedAt"];
+	        this.folders = source["folders"];
+	        this.counts = source["counts"];
+	        this.replays = this.convertValues(source["replays"], ReplayDiagnostic);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReplayFileChange {
+	    oldPath: string;
+	    newPath?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplayFileChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.oldPath = source["oldPath"];
+	        this.newPath = source["newPath"];
+	        this.error = source["error"];
+	    }
+	}
+	export class ReplayFolder {
+	    path: string;
+	    label?: string;
+	    enabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplayFolder(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.label = source["label"];
+	        this.enabled = source["enabled"];
+	    }
+	}
+	
+	
+	
+	
+	
+	
+	export class SettingsProfile {
+	    name: string;
+	    folders?: string[];
+	    playerIds?: string[];
+	    favoritePlayerIds?: string[];
+	    dateRangeFrom?: string;
+	    dateRangeTo?: string;
+	    dailyRecapUser?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SettingsProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.folders = source["folders"];
+	        this.playerIds = source["playerIds"];
+	        this.favoritePlayerIds = source["favoritePlayerIds"];
+	        this.dateRangeFrom = source["dateRangeFrom"];
+	        this.dateRangeTo = source["dateRangeTo"];
+	        this.dailyRecapUser = source["dailyRecapUser"];
 	    }
 	}
 	export class Settings {
+	    schemaVersion: number;
+	    folders?: string[];
 	    playerIds?: string[];
 	    favoritePlayerIds?: string[];
 	    dateRangeFrom?: string;
 	    dateRangeTo?: string;
 	    dailyRecapUser?: string;
+	    dayStartsAt?: string;
+	    timeZone?: string;
+	    replayFolders?: ReplayFolder[];
+	    profiles?: SettingsProfile[];
+	    activeProfile?: string;
+	    updateChannel?: string;
+	    telemetryDisabled?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new Settings(source);
@@ -797,13 +1884,41 @@ export namespace main {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.schemaVersion = source["schemaVersion"];
+	        this.folders = source["folders"];
 	        this.playerIds = source["playerIds"];
 	        this.favoritePlayerIds = source["favoritePlayerIds"];
 	        this.dateRangeFrom = source["dateRangeFrom"];
 	        this.dateRangeTo = source["dateRangeTo"];
 	        this.dailyRecapUser = source["dailyRecapUser"];
+	        this.dayStartsAt = source["dayStartsAt"];
+	        this.timeZone = source["timeZone"];
+	        this.replayFolders = this.convertValues(source["replayFolders"], ReplayFolder);
+	        this.profiles = this.convertValues(source["profiles"], SettingsProfile);
+	        this.activeProfile = source["activeProfile"];
+	        this.updateChannel = source["updateChannel"];
+	        this.telemetryDisabled = source["telemetryDisabled"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
 	export class SteamPlayer {
 	    steamid: string;
 	    communityvisibilitystate: number;
@@ -822,6 +1937,7 @@ export namespace main {
 	    gameextrainfo?: string;
 	    gameid?: string;
 	    loccountrycode?: string;
+	    localAvatar?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new SteamPlayer(source);
@@ -846,28 +1962,41 @@ export namespace main {
 	        this.gameextrainfo = source["gameextrainfo"];
 	        this.gameid = source["gameid"];
 	        this.loccountrycode = source["loccountrycode"];
+	        this.localAvatar = source["localAvatar"];
 	    }
 	}
-	export class Warno {
-	    game: Game;
-	    localPlayerEugenId: string;
-	    localPlayerKey: string;
-	    players: Record<string, Player>;
-	    playerCount: number;
-	    result: Result;
+	export class TeamPoolImportResult {
+	    member: string;
+	    imported: number;
+	    duplicates: number;
+	    files: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new Warno(source);
+	        return new TeamPoolImportResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.game = this.convertValues(source["game"], Game);
-	        this.localPlayerEugenId = source["localPlayerEugenId"];
-	        this.localPlayerKey = source["localPlayerKey"];
-	        this.players = this.convertValues(source["players"], Player, true);
-	        this.playerCount = source["playerCount"];
-	        this.result = this.convertValues(source["result"], Result);
+	        this.member = source["member"];
+	        this.imported = source["imported"];
+	        this.duplicates = source["duplicates"];
+	        this.files = source["files"];
+	    }
+	}
+	export class TeamPoolStats {
+	    team: PoolStats;
+	    internalGames: number;
+	    members: MemberPoolStats[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TeamPoolStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.team = this.convertValues(source["team"], PoolStats);
+	        this.internalGames = source["internalGames"];
+	        this.members = this.convertValues(source["members"], MemberPoolStats);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -888,24 +2017,60 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class WarnoData {
-	    fileName: string;
-	    filePath: string;
-	    key: string;
-	    createdAt: string;
-	    warno: Warno;
+	export class TelemetryInfo {
+	    enabled: boolean;
+	    endpoint: string;
+	    payload: AnalyticsPayload;
 	
 	    static createFrom(source: any = {}) {
-	        return new WarnoData(source);
+	        return new TelemetryInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.fileName = source["fileName"];
-	        this.filePath = source["filePath"];
-	        this.key = source["key"];
-	        this.createdAt = source["createdAt"];
-	        this.warno = this.convertValues(source["warno"], Warno);
+	        this.enabled = source["enabled"];
+	        this.endpoint = source["endpoint"];
+	        this.payload = this.convertValues(source["payload"], AnalyticsPayload);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UpdateStatus {
+	    currentVersion: string;
+	    channel: string;
+	    latest?: ReleaseInfo;
+	    updateAvailable: boolean;
+	    stagedVersion?: string;
+	    updateError?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UpdateStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.currentVersion = source["currentVersion"];
+	        this.channel = source["channel"];
+	        this.latest = this.convertValues(source["latest"], ReleaseInfo);
+	        this.updateAvailable = source["updateAvailable"];
+	        this.stagedVersion = source["stagedVersion"];
+	        this.updateError = source["updateError"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -926,6 +2091,7 @@ export namespace main {
 		    return a;
 		}
 	}
+	
 
 }
 
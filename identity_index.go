@@ -0,0 +1,512 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// steamID64Base turns a 32-bit Steam account ID, as used for the userdata
+// folder names, into a SteamID64.
+const steamID64Base = 76561197960265728
+
+const identitySearchLimit = 50
+
+// identityIndexVersion is bumped when the index needs a rebuild from the
+// replays; version 1 only held local accounts, version 2 did not record the
+// modification time of indexed files.
+const identityIndexVersion = 3
+
+type IdentityName struct {
+	Name      string    `json:"name"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Games     int       `json:"games"`
+}
+
+// PlayerIdentity is an Eugen account seen in the local replays. IsLocal marks
+// accounts that recorded replays on this machine; Folders lists where those
+// replays are. Names is the alias history, oldest first, and CanonicalName the
+// name to display.
+type PlayerIdentity struct {
+	EugenId       string         `json:"eugenId"`
+	SteamId       string         `json:"steamId,omitempty"`
+	IsLocal       bool           `json:"isLocal"`
+	CanonicalName string         `json:"canonicalName"`
+	Names         []IdentityName `json:"names"`
+	Folders       []string       `json:"folders,omitempty"`
+	FirstSeen     time.Time      `json:"firstSeen"`
+	LastSeen      time.Time      `json:"lastSeen"`
+	Games         int            `json:"games"`
+}
+
+// indexedFile identifies the version of a replay file that was indexed, the
+// same way the replay cache does.
+type indexedFile struct {
+	ModTimeUnixNano int64 `json:"modTimeUnixNano"`
+	Size            int64 `json:"size"`
+}
+
+func statIndexedFile(path string) (indexedFile, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return indexedFile{}, false
+	}
+	return indexedFile{ModTimeUnixNano: info.ModTime().UnixNano(), Size: info.Size()}, true
+}
+
+// identityIndex remembers the players seen in parsed replays and which replay
+// files were already indexed, so only new and changed files need parsing.
+type identityIndex struct {
+	mu     sync.Mutex
+	loaded bool
+
+	Version    int                        `json:"version"`
+	Files      map[string]indexedFile     `json:"files"`
+	Identities map[string]*PlayerIdentity `json:"identities"`
+}
+
+var localIdentities = &identityIndex{}
+
+func getIdentityIndexFilePath() (string, error) {
+	dir, err := getLocalAppDataDir("warno-replays-analyser")
+	if err != nil {
+		return "", fmt.Errorf("failed to get app data directory: %w", err)
+	}
+	return filepath.Join(dir, "localIdentities.json"), nil
+}
+
+func (x *identityIndex) load() {
+	if x.loaded {
+		return
+	}
+	x.loaded = true
+	x.reset()
+
+	filePath, err := getIdentityIndexFilePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+	// An unreadable or outdated index is rebuilt from the replay cache.
+	if err := json.Unmarshal(data, x); err != nil || x.Version != identityIndexVersion {
+		x.reset()
+	}
+}
+
+func (x *identityIndex) reset() {
+	x.Version = identityIndexVersion
+	x.Files = make(map[string]indexedFile)
+	x.Identities = make(map[string]*PlayerIdentity)
+}
+
+func (x *identityIndex) save() error {
+	filePath, err := getIdentityIndexFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(x)
+	if err != nil {
+		return fmt.Errorf("marshaling identity index: %w", err)
+	}
+	return writeFileAtomic(filePath, data, 0644)
+}
+
+// steamIdFromReplayPath derives the SteamID64 of the account owning a replay
+// from its Steam userdata path, or returns "" for other folders.
+func steamIdFromReplayPath(path string) string {
+	remote := filepath.Dir(path)
+	game := filepath.Dir(remote)
+	if !strings.EqualFold(filepath.Base(remote), "remote") || filepath.Base(game) != "1611600" {
+		return ""
+	}
+
+	accountID, err := strconv.ParseUint(filepath.Base(filepath.Dir(game)), 10, 32)
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatUint(accountID+steamID64Base, 10)
+}
+
+// steamIdFromAvatar extracts the SteamID64 the game puts at the end of the
+// PlayerAvatar URL.
+func steamIdFromAvatar(avatar string) string {
+	avatar = strings.TrimSpace(avatar)
+	if i := strings.IndexAny(avatar, "?#"); i >= 0 {
+		avatar = avatar[:i]
+	}
+	last := avatar[strings.LastIndex(avatar, "/")+1:]
+
+	id, err := strconv.ParseUint(last, 10, 64)
+	if err != nil || id <= steamID64Base {
+		return ""
+	}
+	return last
+}
+
+func (id *PlayerIdentity) seen(player Player, at time.Time) {
+	id.Games++
+	if at.Before(id.FirstSeen) {
+		id.FirstSeen = at
+	}
+	if at.After(id.LastSeen) {
+		id.LastSeen = at
+	}
+	if steamId := steamIdFromAvatar(player.PlayerAvatar); steamId != "" {
+		id.SteamId = steamId
+	}
+	id.seenAs(player.PlayerName, at)
+}
+
+func (id *PlayerIdentity) seenAs(name string, at time.Time) {
+	for i := range id.Names {
+		n := &id.Names[i]
+		if n.Name != name {
+			continue
+		}
+		n.Games++
+		if at.Before(n.FirstSeen) {
+			n.FirstSeen = at
+		}
+		if at.After(n.LastSeen) {
+			n.LastSeen = at
+		}
+		return
+	}
+	id.Names = append(id.Names, IdentityName{Name: name, FirstSeen: at, LastSeen: at, Games: 1})
+}
+
+// indexed reports whether the current version of path is in the index.
+func (x *identityIndex) indexed(path string) bool {
+	file, ok := x.Files[path]
+	if !ok {
+		return false
+	}
+	current, ok := statIndexedFile(path)
+	return ok && current == file
+}
+
+// update records the players of every replay and marks files as indexed.
+// files must only hold paths that were processed without error; files that
+// yield no replay are remembered too so they are not parsed again.
+func (x *identityIndex) update(files []string, replays []WarnoData) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load()
+
+	processed := make(map[string]struct{}, len(files))
+	for _, path := range files {
+		processed[path] = struct{}{}
+	}
+
+	changed := false
+	for _, replay := range replays {
+		if _, ok := processed[replay.FilePath]; !ok || x.indexed(replay.FilePath) {
+			continue
+		}
+		changed = true
+
+		at, err := time.Parse(time.RFC3339, replay.CreatedAt)
+		if err != nil {
+			continue
+		}
+
+		for key, player := range replay.Warno.Players {
+			if player.PlayerUserId == "" {
+				continue
+			}
+
+			id, ok := x.Identities[player.PlayerUserId]
+			if !ok {
+				id = &PlayerIdentity{EugenId: player.PlayerUserId, FirstSeen: at, LastSeen: at}
+				x.Identities[player.PlayerUserId] = id
+			}
+			id.seen(player, at)
+
+			if key != replay.Warno.LocalPlayerKey {
+				continue
+			}
+			id.IsLocal = true
+			folder := filepath.Dir(replay.FilePath)
+			if !containsString(id.Folders, folder) {
+				id.Folders = append(id.Folders, folder)
+			}
+			if steamId := steamIdFromReplayPath(replay.FilePath); steamId != "" {
+				id.SteamId = steamId
+			}
+		}
+	}
+
+	for _, path := range files {
+		file, ok := statIndexedFile(path)
+		if ok && x.Files[path] != file {
+			x.Files[path] = file
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return x.save()
+}
+
+// missing returns the files that have not been indexed yet or changed since.
+func (x *identityIndex) missing(files []string) []string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load()
+
+	var out []string
+	for _, file := range files {
+		if !x.indexed(file) {
+			out = append(out, file)
+		}
+	}
+	return out
+}
+
+// moved records that an indexed replay was renamed or moved.
+func (x *identityIndex) moved(oldPath, newPath string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load()
+
+	file, ok := x.Files[oldPath]
+	if !ok {
+		return nil
+	}
+	delete(x.Files, oldPath)
+	x.Files[newPath] = file
+	return x.save()
+}
+
+// removed forgets a deleted replay. The players it contained stay indexed.
+func (x *identityIndex) removed(path string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load()
+
+	if _, ok := x.Files[path]; !ok {
+		return nil
+	}
+	delete(x.Files, path)
+	return x.save()
+}
+
+// canonicalName picks the name used most recently; with several names last
+// seen in the same game, the one used most often wins.
+func canonicalName(names []IdentityName) string {
+	var best IdentityName
+	for _, n := range names {
+		if best.Name == "" || n.LastSeen.After(best.LastSeen) ||
+			(n.LastSeen.Equal(best.LastSeen) && n.Games > best.Games) {
+			best = n
+		}
+	}
+	return best.Name
+}
+
+func (id *PlayerIdentity) snapshot() PlayerIdentity {
+	c := *id
+	c.Names = append([]IdentityName(nil), id.Names...)
+	c.Folders = append([]string(nil), id.Folders...)
+	sort.SliceStable(c.Names, func(i, j int) bool { return c.Names[i].FirstSeen.Before(c.Names[j].FirstSeen) })
+	c.CanonicalName = canonicalName(c.Names)
+	return c
+}
+
+func sortIdentitiesByActivity(ids []PlayerIdentity) {
+	sort.Slice(ids, func(i, j int) bool {
+		if !ids[i].LastSeen.Equal(ids[j].LastSeen) {
+			return ids[i].LastSeen.After(ids[j].LastSeen)
+		}
+		return ids[i].EugenId < ids[j].EugenId
+	})
+}
+
+// localIdentitiesIn returns the local accounts with replays in any of folders,
+// or all local accounts when folders is empty, most recently active first.
+func (x *identityIndex) localIdentitiesIn(folders []string) []PlayerIdentity {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load()
+
+	inScope := func(id *PlayerIdentity) bool {
+		if len(folders) == 0 {
+			return true
+		}
+		for _, f := range id.Folders {
+			for _, scope := range folders {
+				if sameFolder(f, scope) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	out := []PlayerIdentity{}
+	for _, id := range x.Identities {
+		if id.IsLocal && inScope(id) {
+			out = append(out, id.snapshot())
+		}
+	}
+	sortIdentitiesByActivity(out)
+
+	return out
+}
+
+// resolve returns the identities known for ids; unknown IDs are left out.
+func (x *identityIndex) resolve(ids []string) map[string]PlayerIdentity {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load()
+
+	out := make(map[string]PlayerIdentity, len(ids))
+	for _, eugenId := range ids {
+		if id, ok := x.Identities[eugenId]; ok {
+			out[eugenId] = id.snapshot()
+		}
+	}
+	return out
+}
+
+// steamIds returns the SteamIDs of all indexed players.
+func (x *identityIndex) steamIds() []string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load()
+
+	var ids []string
+	for _, id := range x.Identities {
+		if id.SteamId != "" {
+			ids = append(ids, id.SteamId)
+		}
+	}
+	return ids
+}
+
+// identityMatchScore ranks how well query matches a player: the Eugen or
+// Steam ID, then the current name, then older aliases. 0 means no match.
+func identityMatchScore(id PlayerIdentity, query string) int {
+	if id.EugenId == query || (id.SteamId != "" && id.SteamId == query) {
+		return 100
+	}
+
+	best := 0
+	for _, n := range id.Names {
+		name := strings.ToLower(n.Name)
+		score := 0
+		switch {
+		case name == query:
+			score = 40
+		case strings.HasPrefix(name, query):
+			score = 20
+		case strings.Contains(name, query):
+			score = 10
+		}
+		if score > 0 && n.Name == id.CanonicalName {
+			score += 5
+		}
+		if score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+func (x *identityIndex) search(query string, limit int) []PlayerIdentity {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load()
+
+	type match struct {
+		id    PlayerIdentity
+		score int
+	}
+	var matches []match
+	for _, id := range x.Identities {
+		snapshot := id.snapshot()
+		if score := identityMatchScore(snapshot, query); score > 0 {
+			matches = append(matches, match{snapshot, score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		if matches[i].id.Games != matches[j].id.Games {
+			return matches[i].id.Games > matches[j].id.Games
+		}
+		return matches[i].id.EugenId < matches[j].id.EugenId
+	})
+
+	out := []PlayerIdentity{}
+	for _, m := range matches {
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		out = append(out, m.id)
+	}
+	return out
+}
+
+// indexReplayFolders parses the replays of folders that are not indexed yet.
+func indexReplayFolders(folders []string) {
+	files := listReplayFiles(folders)
+	missing := localIdentities.missing(files)
+	if len(missing) == 0 {
+		return
+	}
+
+	replays, processed := processReplayFiles(missing)
+	if err := localIdentities.update(processed, replays); err != nil {
+		fmt.Printf("failed to update local identities: %v\n", err)
+	}
+}
+
+// refreshIdentities indexes the replays of all known folders that are not in
+// the index yet.
+func (a *App) refreshIdentities() {
+	indexReplayFolders(a.replayDirectories())
+}
+
+// GetLocalIdentities returns the Eugen accounts that recorded the local
+// replays.
+func (a *App) GetLocalIdentities() []PlayerIdentity {
+	a.refreshIdentities()
+
+	return localIdentities.localIdentitiesIn(nil)
+}
+
+// ResolvePlayerIdentities returns the identity of every known player in ids,
+// keyed by Eugen ID.
+func (a *App) ResolvePlayerIdentities(ids []string) map[string]PlayerIdentity {
+	a.refreshIdentities()
+
+	return localIdentities.resolve(ids)
+}
+
+// SearchPlayerIdentities finds players seen in the local replays by Eugen ID,
+// SteamID, current name or any former alias.
+func (a *App) SearchPlayerIdentities(query string) []PlayerIdentity {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return []PlayerIdentity{}
+	}
+
+	a.refreshIdentities()
+
+	return localIdentities.search(query, identitySearchLimit)
+}
@@ -74,6 +74,8 @@ type WarnoData struct {
 	Key       string `json:"key"`
 	CreatedAt string `json:"createdAt"`
 	Warno     Warno  `json:"warno"`
+	// Tags are the user's tags of the game, filled in by GetReplays.
+	Tags []string `json:"tags,omitempty"`
 }
 
 type KeyPlayerPair struct {
@@ -2,9 +2,12 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 )
@@ -23,63 +26,122 @@ type EugenAPIResponse struct {
 
 type LeaderboardEntry struct {
 	ID   int     `json:"id"`
+	Rank int     `json:"rank"`
 	Elo  float64 `json:"elo"`
 	Name string  `json:"name"`
 }
 
-func (a *App) GetLeaderboard() ([]LeaderboardEntry, error) {
+// playerLookupBatchSize is the number of Eugen IDs resolved per player API request.
+const playerLookupBatchSize = 100
+
+func fetchEugenLeaderboard() (EugenAPIResponse, error) {
+	var eugen EugenAPIResponse
+
 	eugenResp, err := http.Get(eugenApiUrl + "/stats/_design/LB29/_view/freezed_ELO")
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch Eugen API: %w", err)
+		return eugen, fmt.Errorf("failed to fetch Eugen API: %w", err)
 	}
 	defer eugenResp.Body.Close()
 
+	if eugenResp.StatusCode != http.StatusOK {
+		return eugen, fmt.Errorf("unexpected Eugen API status code: %d", eugenResp.StatusCode)
+	}
+
 	eugenBody, err := io.ReadAll(eugenResp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read Eugen response: %w", err)
+		return eugen, fmt.Errorf("failed to read Eugen response: %w", err)
 	}
 
-	var eugen EugenAPIResponse
 	if err := json.Unmarshal(eugenBody, &eugen); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal Eugen API: %w", err)
+		return eugen, fmt.Errorf("failed to unmarshal Eugen API: %w", err)
+	}
+
+	return eugen, nil
+}
+
+func joinUsernames(usernames []string) string {
+	var nonEmptyNames []string
+	for _, username := range usernames {
+		if username != "" {
+			nonEmptyNames = append(nonEmptyNames, username)
+		}
+	}
+	return strings.Join(nonEmptyNames, ", ")
+}
+
+// fetchPlayerUsernames resolves ids through the player API in batches. Names of
+// the batches that succeeded are returned even when others failed; IDs the API
+// does not know map to an empty name.
+func fetchPlayerUsernames(ids []int) (map[int]string, error) {
+	if apiUrl == "" || apiKey == "" {
+		return nil, errors.New("API_URL or API_KEY is not set")
 	}
 
 	headers := map[string]string{
 		"Authorization": "Bearer " + apiKey,
 	}
 
-	resp, err := makeRequest("GET", apiUrl+"/players", nil, headers)
+	names := make(map[int]string, len(ids))
+	var errs []error
+
+	for start := 0; start < len(ids); start += playerLookupBatchSize {
+		batch := ids[start:min(start+playerLookupBatchSize, len(ids))]
+
+		idStrings := make([]string, len(batch))
+		for i, id := range batch {
+			idStrings[i] = strconv.Itoa(id)
+		}
+
+		query := fmt.Sprintf("%s/players?ids=%s", apiUrl, url.QueryEscape(strings.Join(idStrings, ",")))
+
+		players, err := fetchPlayerBatch(query, headers)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, id := range batch {
+			names[id] = ""
+		}
+		for _, p := range players {
+			names[int(p.EugenId)] = joinUsernames(p.Usernames)
+		}
+	}
+
+	return names, errors.Join(errs...)
+}
+
+func fetchPlayerBatch(query string, headers map[string]string) ([]GetUser, error) {
+	resp, err := makeRequest("GET", query, nil, headers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to player API: %w", err)
 	}
 	defer resp.Body.Close()
 
-	playerBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read player API response: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected player API status code: %d", resp.StatusCode)
 	}
 
 	var players []GetUser
-	if err := json.Unmarshal(playerBody, &players); err != nil {
+	if err := json.NewDecoder(resp.Body).Decode(&players); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal player API: %w", err)
 	}
 
-	playerMap := make(map[int]string)
-	for _, p := range players {
-		var nonEmptyNames []string
-		for _, username := range p.Usernames {
-			if username != "" {
-				nonEmptyNames = append(nonEmptyNames, username)
-			}
-		}
-		name := ""
-		if len(nonEmptyNames) > 0 {
-			name = strings.Join(nonEmptyNames, ", ")
-		}
-		playerMap[int(p.EugenId)] = name
+	return players, nil
+}
+
+// GetLeaderboard returns the Eugen leaderboard joined with the usernames known
+// by the player API. Only names missing from the local cache or older than
+// playerNameCacheTTL are requested; when the player API fails the Elo rows are
+// still returned with cached or unknown names.
+func (a *App) GetLeaderboard() ([]LeaderboardEntry, error) {
+	eugen, err := fetchEugenLeaderboard()
+	if err != nil {
+		return nil, err
 	}
 
 	var leaderboard []LeaderboardEntry
+	var ids []int
 	for _, row := range eugen.Rows {
 		parts := strings.Split(row.ID, "_")
 		if len(parts) != 2 {
@@ -93,16 +155,56 @@ func (a *App) GetLeaderboard() ([]LeaderboardEntry, error) {
 		if err != nil {
 			continue
 		}
-		name, found := playerMap[idNum]
-		if !found {
-			name = "unknown"
-		}
+		ids = append(ids, idNum)
 		leaderboard = append(leaderboard, LeaderboardEntry{
-			ID:   idNum,
-			Elo:  elo,
-			Name: name,
+			ID:  idNum,
+			Elo: elo,
 		})
 	}
 
+	playerMap, stale := playerNames.lookup(ids)
+	if len(stale) > 0 {
+		fetched, err := fetchPlayerUsernames(stale)
+		if err != nil {
+			log.Printf("Error resolving leaderboard names (%d of %d resolved): %v", len(fetched), len(stale), err)
+		}
+		for id, name := range fetched {
+			if name != "" {
+				playerMap[id] = name
+			}
+		}
+		if len(fetched) > 0 {
+			if err := playerNames.store(fetched); err != nil {
+				log.Printf("Error saving player name cache: %v", err)
+			}
+		}
+	}
+
+	// Players the API does not know may still be opponents from local replays.
+	var unnamed []string
+	for _, id := range ids {
+		if playerMap[id] == "" {
+			unnamed = append(unnamed, strconv.Itoa(id))
+		}
+	}
+	known := localIdentities.resolve(unnamed)
+
+	for i := range leaderboard {
+		name := playerMap[leaderboard[i].ID]
+		if name == "" {
+			name = known[strconv.Itoa(leaderboard[i].ID)].CanonicalName
+		}
+		if name == "" {
+			name = "unknown"
+		}
+		leaderboard[i].Name = name
+	}
+
+	assignLeaderboardRanks(leaderboard)
+
+	if err := storeLeaderboardSnapshot(leaderboard); err != nil {
+		log.Printf("Error storing leaderboard snapshot: %v", err)
+	}
+
 	return leaderboard, nil
 }
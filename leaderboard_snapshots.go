@@ -0,0 +1,419 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// leaderboardSnapshotLimit caps the number of snapshots kept on disk; the
+// oldest ones are removed first.
+const leaderboardSnapshotLimit = 500
+
+type LeaderboardSnapshot struct {
+	ID      string             `json:"id"`
+	TakenAt time.Time          `json:"takenAt"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+type LeaderboardSnapshotInfo struct {
+	ID      string    `json:"id"`
+	TakenAt time.Time `json:"takenAt"`
+	Players int       `json:"players"`
+}
+
+// LeaderboardMovement describes how a player present in both snapshots moved.
+// A positive RankChange means the player climbed.
+type LeaderboardMovement struct {
+	ID           int     `json:"id"`
+	Name         string  `json:"name"`
+	PreviousRank int     `json:"previousRank"`
+	Rank         int     `json:"rank"`
+	RankChange   int     `json:"rankChange"`
+	PreviousElo  float64 `json:"previousElo"`
+	Elo          float64 `json:"elo"`
+	EloChange    float64 `json:"eloChange"`
+}
+
+type LeaderboardDiff struct {
+	From        LeaderboardSnapshotInfo `json:"from"`
+	To          LeaderboardSnapshotInfo `json:"to"`
+	Movements   []LeaderboardMovement   `json:"movements"`
+	NewEntrants []LeaderboardEntry      `json:"newEntrants"`
+	Dropouts    []LeaderboardEntry      `json:"dropouts"`
+}
+
+type LeaderboardTrajectoryPoint struct {
+	SnapshotID string    `json:"snapshotId"`
+	TakenAt    time.Time `json:"takenAt"`
+	Rank       int       `json:"rank"`
+	Elo        float64   `json:"elo"`
+}
+
+var leaderboardSnapshotsMu sync.Mutex
+
+func getLeaderboardSnapshotsDir() (string, error) {
+	dir, err := getLocalAppDataDir("warno-replays-analyser", "leaderboardSnapshots")
+	if err != nil {
+		return "", fmt.Errorf("getting leaderboardSnapshots directory: %w", err)
+	}
+	return dir, nil
+}
+
+// leaderboardSnapshotFile is a stored snapshot as listed on disk. Files are
+// named "<id>_<players>.json" so the list needs no decoding; IDs are the Unix
+// nanosecond timestamps of the fetch.
+type leaderboardSnapshotFile struct {
+	id      string
+	takenAt time.Time
+	// players is -1 for files named "<id>.json" by older versions.
+	players int
+	name    string
+}
+
+func leaderboardSnapshotFileName(id string, players int) string {
+	return fmt.Sprintf("%s_%d.json", id, players)
+}
+
+// listLeaderboardSnapshots returns the stored snapshots, oldest first.
+func listLeaderboardSnapshots(dir string) ([]leaderboardSnapshotFile, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []leaderboardSnapshotFile
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		idPart, playersPart, counted := strings.Cut(strings.TrimSuffix(name, ".json"), "_")
+		nanos, err := strconv.ParseInt(idPart, 10, 64)
+		if err != nil {
+			continue
+		}
+		players := -1
+		if counted {
+			if players, err = strconv.Atoi(playersPart); err != nil {
+				continue
+			}
+		}
+
+		snapshots = append(snapshots, leaderboardSnapshotFile{
+			id:      idPart,
+			takenAt: time.Unix(0, nanos).UTC(),
+			players: players,
+			name:    name,
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].takenAt.Before(snapshots[j].takenAt) })
+
+	return snapshots, nil
+}
+
+func findLeaderboardSnapshot(files []leaderboardSnapshotFile, id string) (leaderboardSnapshotFile, error) {
+	for _, f := range files {
+		if f.id == id {
+			return f, nil
+		}
+	}
+	return leaderboardSnapshotFile{}, fmt.Errorf("leaderboard snapshot %s not found", id)
+}
+
+func loadLeaderboardSnapshot(dir string, file leaderboardSnapshotFile) (LeaderboardSnapshot, error) {
+	var snapshot LeaderboardSnapshot
+
+	data, err := os.ReadFile(filepath.Join(dir, file.name))
+	if err != nil {
+		return snapshot, fmt.Errorf("reading snapshot %s: %w", file.id, err)
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("unmarshaling snapshot %s: %w", file.id, err)
+	}
+
+	return snapshot, nil
+}
+
+// sameStandings reports whether two snapshots rank the same players at the
+// same rank and Elo. Names are left out, as they change with the name cache
+// rather than with the leaderboard.
+func sameStandings(a, b []LeaderboardEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	standings := make(map[int]LeaderboardEntry, len(a))
+	for _, e := range a {
+		standings[e.ID] = e
+	}
+	for _, e := range b {
+		prev, ok := standings[e.ID]
+		if !ok || prev.Rank != e.Rank || prev.Elo != e.Elo {
+			return false
+		}
+	}
+	return true
+}
+
+// storeLeaderboardSnapshot saves entries as a new snapshot unless they are
+// identical to the latest one, then prunes snapshots beyond the limit.
+func storeLeaderboardSnapshot(entries []LeaderboardEntry) error {
+	leaderboardSnapshotsMu.Lock()
+	defer leaderboardSnapshotsMu.Unlock()
+
+	dir, err := getLeaderboardSnapshotsDir()
+	if err != nil {
+		return err
+	}
+
+	files, err := listLeaderboardSnapshots(dir)
+	if err != nil {
+		return err
+	}
+
+	if len(files) > 0 {
+		latest, err := loadLeaderboardSnapshot(dir, files[len(files)-1])
+		if err == nil && sameStandings(latest.Entries, entries) {
+			return nil
+		}
+	}
+
+	now := time.Now().UTC()
+	snapshot := LeaderboardSnapshot{
+		ID:      strconv.FormatInt(now.UnixNano(), 10),
+		TakenAt: now,
+		Entries: entries,
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("marshaling snapshot: %w", err)
+	}
+	name := leaderboardSnapshotFileName(snapshot.ID, len(entries))
+	if err := writeFileAtomic(filepath.Join(dir, name), data, 0644); err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+
+	for len(files)+1 > leaderboardSnapshotLimit {
+		if err := os.Remove(filepath.Join(dir, files[0].name)); err != nil {
+			log.Printf("Error removing leaderboard snapshot %s: %v", files[0].id, err)
+		}
+		files = files[1:]
+	}
+
+	return nil
+}
+
+// assignLeaderboardRanks sets Rank on every entry from its Elo, highest first.
+// Players with the same Elo share a rank.
+func assignLeaderboardRanks(entries []LeaderboardEntry) {
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return entries[order[i]].Elo > entries[order[j]].Elo
+	})
+
+	for pos, idx := range order {
+		if pos > 0 && entries[idx].Elo == entries[order[pos-1]].Elo {
+			entries[idx].Rank = entries[order[pos-1]].Rank
+			continue
+		}
+		entries[idx].Rank = pos + 1
+	}
+}
+
+func snapshotInfo(s LeaderboardSnapshot) LeaderboardSnapshotInfo {
+	return LeaderboardSnapshotInfo{ID: s.ID, TakenAt: s.TakenAt, Players: len(s.Entries)}
+}
+
+func diffLeaderboardSnapshots(from, to LeaderboardSnapshot) LeaderboardDiff {
+	diff := LeaderboardDiff{
+		From:        snapshotInfo(from),
+		To:          snapshotInfo(to),
+		Movements:   []LeaderboardMovement{},
+		NewEntrants: []LeaderboardEntry{},
+		Dropouts:    []LeaderboardEntry{},
+	}
+
+	previous := make(map[int]LeaderboardEntry, len(from.Entries))
+	for _, e := range from.Entries {
+		previous[e.ID] = e
+	}
+	current := make(map[int]struct{}, len(to.Entries))
+
+	for _, e := range to.Entries {
+		current[e.ID] = struct{}{}
+
+		prev, ok := previous[e.ID]
+		if !ok {
+			diff.NewEntrants = append(diff.NewEntrants, e)
+			continue
+		}
+
+		diff.Movements = append(diff.Movements, LeaderboardMovement{
+			ID:           e.ID,
+			Name:         e.Name,
+			PreviousRank: prev.Rank,
+			Rank:         e.Rank,
+			RankChange:   prev.Rank - e.Rank,
+			PreviousElo:  prev.Elo,
+			Elo:          e.Elo,
+			EloChange:    e.Elo - prev.Elo,
+		})
+	}
+
+	for _, e := range from.Entries {
+		if _, ok := current[e.ID]; !ok {
+			diff.Dropouts = append(diff.Dropouts, e)
+		}
+	}
+
+	sort.Slice(diff.Movements, func(i, j int) bool { return diff.Movements[i].Rank < diff.Movements[j].Rank })
+	sort.Slice(diff.NewEntrants, func(i, j int) bool { return diff.NewEntrants[i].Rank < diff.NewEntrants[j].Rank })
+	sort.Slice(diff.Dropouts, func(i, j int) bool { return diff.Dropouts[i].Rank < diff.Dropouts[j].Rank })
+
+	return diff
+}
+
+// GetLeaderboardSnapshots lists the stored leaderboard snapshots, newest first.
+// Only snapshots stored by older versions are read to count their players.
+func (a *App) GetLeaderboardSnapshots() []LeaderboardSnapshotInfo {
+	leaderboardSnapshotsMu.Lock()
+	defer leaderboardSnapshotsMu.Unlock()
+
+	dir, err := getLeaderboardSnapshotsDir()
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return []LeaderboardSnapshotInfo{}
+	}
+
+	files, err := listLeaderboardSnapshots(dir)
+	if err != nil {
+		log.Printf("Error listing leaderboard snapshots: %v", err)
+		return []LeaderboardSnapshotInfo{}
+	}
+
+	infos := make([]LeaderboardSnapshotInfo, 0, len(files))
+	for i := len(files) - 1; i >= 0; i-- {
+		f := files[i]
+		if f.players < 0 {
+			snapshot, err := loadLeaderboardSnapshot(dir, f)
+			if err != nil {
+				log.Printf("Error: %v", err)
+				continue
+			}
+			f.players = len(snapshot.Entries)
+		}
+		infos = append(infos, LeaderboardSnapshotInfo{ID: f.id, TakenAt: f.takenAt, Players: f.players})
+	}
+
+	return infos
+}
+
+// GetLeaderboardDiff compares two snapshots. Empty IDs default to the two most
+// recent snapshots.
+func (a *App) GetLeaderboardDiff(fromId, toId string) (LeaderboardDiff, error) {
+	leaderboardSnapshotsMu.Lock()
+	defer leaderboardSnapshotsMu.Unlock()
+
+	dir, err := getLeaderboardSnapshotsDir()
+	if err != nil {
+		return LeaderboardDiff{}, err
+	}
+
+	files, err := listLeaderboardSnapshots(dir)
+	if err != nil {
+		return LeaderboardDiff{}, fmt.Errorf("listing leaderboard snapshots: %w", err)
+	}
+
+	if toId == "" {
+		if len(files) == 0 {
+			return LeaderboardDiff{}, fmt.Errorf("no leaderboard snapshots stored")
+		}
+		toId = files[len(files)-1].id
+	}
+	if fromId == "" {
+		for i, f := range files {
+			if f.id == toId && i > 0 {
+				fromId = files[i-1].id
+				break
+			}
+		}
+		if fromId == "" {
+			return LeaderboardDiff{}, fmt.Errorf("no leaderboard snapshot before %s", toId)
+		}
+	}
+
+	fromFile, err := findLeaderboardSnapshot(files, fromId)
+	if err != nil {
+		return LeaderboardDiff{}, err
+	}
+	toFile, err := findLeaderboardSnapshot(files, toId)
+	if err != nil {
+		return LeaderboardDiff{}, err
+	}
+
+	from, err := loadLeaderboardSnapshot(dir, fromFile)
+	if err != nil {
+		return LeaderboardDiff{}, err
+	}
+	to, err := loadLeaderboardSnapshot(dir, toFile)
+	if err != nil {
+		return LeaderboardDiff{}, err
+	}
+
+	return diffLeaderboardSnapshots(from, to), nil
+}
+
+// GetLeaderboardPlayerTrajectory returns the rank and Elo of a player in every
+// snapshot the player appears in, oldest first.
+func (a *App) GetLeaderboardPlayerTrajectory(playerId int) []LeaderboardTrajectoryPoint {
+	leaderboardSnapshotsMu.Lock()
+	defer leaderboardSnapshotsMu.Unlock()
+
+	points := []LeaderboardTrajectoryPoint{}
+
+	dir, err := getLeaderboardSnapshotsDir()
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return points
+	}
+
+	files, err := listLeaderboardSnapshots(dir)
+	if err != nil {
+		log.Printf("Error listing leaderboard snapshots: %v", err)
+		return points
+	}
+
+	for _, f := range files {
+		snapshot, err := loadLeaderboardSnapshot(dir, f)
+		if err != nil {
+			log.Printf("Error: %v", err)
+			continue
+		}
+		for _, e := range snapshot.Entries {
+			if e.ID == playerId {
+				points = append(points, LeaderboardTrajectoryPoint{
+					SnapshotID: snapshot.ID,
+					TakenAt:    snapshot.TakenAt,
+					Rank:       e.Rank,
+					Elo:        e.Elo,
+				})
+				break
+			}
+		}
+	}
+
+	return points
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type winrateCounter struct {
+	wins   int
+	losses int
+	draws  int
+}
+
+func (c *winrateCounter) add(outcome int) {
+	switch {
+	case outcome > 0:
+		c.wins++
+	case outcome < 0:
+		c.losses++
+	default:
+		c.draws++
+	}
+}
+
+func (c winrateCounter) games() int {
+	return c.wins + c.losses + c.draws
+}
+
+func (c winrateCounter) winRate() float64 {
+	if c.wins+c.losses == 0 {
+		return 0
+	}
+	return float64(c.wins) / float64(c.wins+c.losses)
+}
+
+type divisionPair struct {
+	division         int
+	opponentDivision int
+}
+
+type rankedReplaySide struct {
+	eugenID  uint
+	division int
+}
+
+// matchesLocally applies the filter to a single replay. Both players have to
+// satisfy the rank and Elo limits, and one of them has to play one of the
+// requested divisions.
+func (f RankedReplaysAnalyticsFilter) matchesLocally(r RankedReplayInput, from, to time.Time) bool {
+	if f.MinDuration > 0 && r.Duration < f.MinDuration {
+		return false
+	}
+
+	if !from.IsZero() || !to.IsZero() {
+		if r.PlayedAt == nil {
+			return false
+		}
+		if !from.IsZero() && r.PlayedAt.Before(from) {
+			return false
+		}
+		if !to.IsZero() && r.PlayedAt.After(to) {
+			return false
+		}
+	}
+
+	if len(f.Versions) > 0 && !containsString(f.Versions, r.Version) {
+		return false
+	}
+	if len(f.Maps) > 0 && !containsString(f.Maps, r.Map) {
+		return false
+	}
+	if len(f.Divisions) > 0 && !containsInt(f.Divisions, r.Player1Division) && !containsInt(f.Divisions, r.Player2Division) {
+		return false
+	}
+
+	for _, rank := range []*int{r.Player1Rank, r.Player2Rank} {
+		if f.MaxRank > 0 && (rank == nil || *rank <= 0 || *rank > f.MaxRank) {
+			return false
+		}
+	}
+	for _, elo := range []*int{r.Player1Elo, r.Player2Elo} {
+		if f.MinElo > 0 && (elo == nil || *elo < f.MinElo) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// buildRankedReplaysAnalytics aggregates replays into the same shape the remote
+// analytics endpoint returns. Replays sharing an EugenID (session ID) are
+// counted once, so pooling folders of both players of a game is safe. Every
+// game contributes a result for each side.
+func buildRankedReplaysAnalytics(replays []RankedReplayInput, filter RankedReplaysAnalyticsFilter) RankedReplaysAnalyticsResponse {
+	var from, to time.Time
+	if filter.From != "" {
+		if t, err := parseFilterTime(filter.From); err == nil {
+			from = t
+		} else {
+			log.Printf("Ignoring invalid analytics filter from=%q: %v", filter.From, err)
+		}
+	}
+	if filter.To != "" {
+		if t, err := parseFilterTime(filter.To); err == nil {
+			to = t
+		} else {
+			log.Printf("Ignoring invalid analytics filter to=%q: %v", filter.To, err)
+		}
+	}
+
+	seen := make(map[string]struct{})
+	submitters := make(map[uint]struct{})
+
+	divisions := make(map[int]*winrateCounter)
+	divisionVs := make(map[divisionPair]*winrateCounter)
+	divisionOnMap := make(map[string]map[int]*winrateCounter)
+	divisionOnMapVs := make(map[string]map[divisionPair]*winrateCounter)
+
+	counter := func(m map[int]*winrateCounter, key int) *winrateCounter {
+		if m[key] == nil {
+			m[key] = &winrateCounter{}
+		}
+		return m[key]
+	}
+	pairCounter := func(m map[divisionPair]*winrateCounter, key divisionPair) *winrateCounter {
+		if m[key] == nil {
+			m[key] = &winrateCounter{}
+		}
+		return m[key]
+	}
+
+	var resp RankedReplaysAnalyticsResponse
+
+	for _, r := range replays {
+		if r.Player1Division <= 0 || r.Player2Division <= 0 || r.Map == "" {
+			continue
+		}
+		if r.EugenID != nil && *r.EugenID != "" {
+			if _, dup := seen[*r.EugenID]; dup {
+				continue
+			}
+			seen[*r.EugenID] = struct{}{}
+		}
+		if !filter.matchesLocally(r, from, to) {
+			continue
+		}
+
+		resp.TotalGames++
+		submitters[r.SubmittedByEugenID] = struct{}{}
+
+		sides := [2]rankedReplaySide{
+			{eugenID: r.Player1EugenID, division: r.Player1Division},
+			{eugenID: r.Player2EugenID, division: r.Player2Division},
+		}
+		for i, side := range sides {
+			opponent := sides[1-i]
+
+			outcome := 0
+			if r.WinnerPlayerEugenID != nil {
+				if *r.WinnerPlayerEugenID == side.eugenID {
+					outcome = 1
+				} else {
+					outcome = -1
+				}
+			}
+
+			pair := divisionPair{division: side.division, opponentDivision: opponent.division}
+
+			counter(divisions, side.division).add(outcome)
+			pairCounter(divisionVs, pair).add(outcome)
+
+			if divisionOnMap[r.Map] == nil {
+				divisionOnMap[r.Map] = make(map[int]*winrateCounter)
+				divisionOnMapVs[r.Map] = make(map[divisionPair]*winrateCounter)
+			}
+			counter(divisionOnMap[r.Map], side.division).add(outcome)
+			pairCounter(divisionOnMapVs[r.Map], pair).add(outcome)
+		}
+	}
+
+	resp.UniqueSubmitters = len(submitters)
+	resp.Divisions = divisionRows(divisions)
+	resp.DivisionVs = divisionVsGroups(divisionVs)
+
+	for _, mapKey := range sortedMapKeys(divisionOnMap) {
+		resp.DivisionOnMap = append(resp.DivisionOnMap, DivisionOnMapGroup{
+			Map:       mapKey,
+			Divisions: divisionRows(divisionOnMap[mapKey]),
+		})
+
+		var groups []DivisionOnMapVsDivisionGroup
+		for _, group := range divisionVsGroups(divisionOnMapVs[mapKey]) {
+			opponents := make([]DivisionOnMapVsOpponentStats, len(group.Opponents))
+			for i, o := range group.Opponents {
+				opponents[i] = DivisionOnMapVsOpponentStats(o)
+			}
+			groups = append(groups, DivisionOnMapVsDivisionGroup{Division: group.Division, Opponents: opponents})
+		}
+		resp.DivisionOnMapVs = append(resp.DivisionOnMapVs, DivisionOnMapVsGroup{Map: mapKey, Divisions: groups})
+	}
+
+	annotateRankedAnalytics(&resp)
+
+	return resp
+}
+
+func divisionRows(counters map[int]*winrateCounter) []DivisionWinrateRow {
+	rows := make([]DivisionWinrateRow, 0, len(counters))
+	for division, c := range counters {
+		rows = append(rows, DivisionWinrateRow{
+			Division:     division,
+			Games:        c.games(),
+			NonDrawGames: c.wins + c.losses,
+			Wins:         c.wins,
+			Losses:       c.losses,
+			Draws:        c.draws,
+			WinRate:      c.winRate(),
+		})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Games != rows[j].Games {
+			return rows[i].Games > rows[j].Games
+		}
+		return rows[i].Division < rows[j].Division
+	})
+
+	return rows
+}
+
+func divisionVsGroups(counters map[divisionPair]*winrateCounter) []DivisionVsGroup {
+	byDivision := make(map[int][]DivisionVsOpponentStats)
+	for pair, c := range counters {
+		byDivision[pair.division] = append(byDivision[pair.division], DivisionVsOpponentStats{
+			OpponentDivision: pair.opponentDivision,
+			Games:            c.games(),
+			NonDrawGames:     c.wins + c.losses,
+			Wins:             c.wins,
+			Losses:           c.losses,
+			Draws:            c.draws,
+			WinRate:          c.winRate(),
+		})
+	}
+
+	groups := make([]DivisionVsGroup, 0, len(byDivision))
+	for division, opponents := range byDivision {
+		sort.Slice(opponents, func(i, j int) bool {
+			if opponents[i].Games != opponents[j].Games {
+				return opponents[i].Games > opponents[j].Games
+			}
+			return opponents[i].OpponentDivision < opponents[j].OpponentDivision
+		})
+		groups = append(groups, DivisionVsGroup{Division: division, Opponents: opponents})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Division < groups[j].Division
+	})
+
+	return groups
+}
+
+func sortedMapKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// positiveInt parses value, keeping only positive numbers as rank and Elo do.
+func positiveInt(value string) *int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n <= 0 {
+		return nil
+	}
+	return &n
+}
+
+// rankedReplayInputs turns replays into the inputs the ranked analytics take,
+// seen from the local player's side like the ones sent to the remote API.
+// Replays without both players, both divisions, a map or a duration are
+// skipped.
+func rankedReplayInputs(replays []WarnoData) []RankedReplayInput {
+	inputs := make([]RankedReplayInput, 0, len(replays))
+
+	for _, replay := range replays {
+		player, ok := replay.Warno.Players[replay.Warno.LocalPlayerKey]
+		if !ok {
+			continue
+		}
+		var enemy Player
+		for key, p := range replay.Warno.Players {
+			if key != replay.Warno.LocalPlayerKey {
+				enemy = p
+			}
+		}
+
+		player1ID, err1 := strconv.ParseUint(player.PlayerUserId, 10, 0)
+		player2ID, err2 := strconv.ParseUint(enemy.PlayerUserId, 10, 0)
+		if err1 != nil || err2 != nil || player1ID == player2ID {
+			continue
+		}
+
+		player1Division := deckDivisionId(player.PlayerDeckContent)
+		player2Division := deckDivisionId(enemy.PlayerDeckContent)
+		if player1Division == 0 || player2Division == 0 {
+			continue
+		}
+
+		mapKey := strings.TrimSpace(replay.Warno.Game.Map)
+		duration, _ := strconv.Atoi(replay.Warno.Result.Duration)
+		if mapKey == "" || duration <= 0 {
+			continue
+		}
+
+		player1Name := strings.TrimSpace(player.PlayerName)
+		player2Name := strings.TrimSpace(enemy.PlayerName)
+		if player1Name == "" || player2Name == "" || player1Name == "Unknown" || player2Name == "Unknown" {
+			continue
+		}
+
+		input := RankedReplayInput{
+			Player1EugenID:     uint(player1ID),
+			Player2EugenID:     uint(player2ID),
+			Player1Elo:         positiveInt(player.PlayerElo),
+			Player1Rank:        positiveInt(player.PlayerRank),
+			Player2Elo:         positiveInt(enemy.PlayerElo),
+			Player2Rank:        positiveInt(enemy.PlayerRank),
+			Player1Name:        player1Name,
+			Player2Name:        player2Name,
+			Player1Division:    player1Division,
+			Player2Division:    player2Division,
+			Map:                mapKey,
+			Version:            replay.Warno.Game.Version,
+			Duration:           duration,
+			SubmittedByEugenID: uint(player1ID),
+		}
+		if sessionID := strings.TrimSpace(replay.Warno.Game.UniqueSessionId); sessionID != "" {
+			input.EugenID = &sessionID
+		}
+		switch replayOutcome(replay.Warno.Result.Victory) {
+		case 1:
+			input.WinnerPlayerEugenID = &input.Player1EugenID
+		case -1:
+			input.WinnerPlayerEugenID = &input.Player2EugenID
+		}
+		if playedAt, err := time.Parse(time.RFC3339, replay.CreatedAt); err == nil {
+			input.PlayedAt = &playedAt
+		}
+
+		inputs = append(inputs, input)
+	}
+
+	return inputs
+}
+
+// GetLocalRankedReplaysAnalytics computes ranked-replay analytics from the
+// replays in directories without sending anything to the remote API.
+func (a *App) GetLocalRankedReplaysAnalytics(directories []string, filter RankedReplaysAnalyticsFilter) RankedReplaysAnalyticsResponse {
+	replays := rankedReplayInputs(getReplays(directories))
+	result := buildRankedReplaysAnalytics(replays, filter)
+
+	log.Printf(
+		"GetLocalRankedReplaysAnalytics ok replays=%d totalGames=%d uniqueSubmitters=%d",
+		len(replays),
+		result.TotalGames,
+		result.UniqueSubmitters,
+	)
+
+	return sortedRankedAnalytics(result, filter.SortBy)
+}
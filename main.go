@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"embed"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -15,14 +17,16 @@ var apiUrl string
 var apiKey string
 var steamApiKey string
 var eugenApiUrl string
+var eugnetApiUrl string
 
 //go:embed all:frontend/dist
 var assets embed.FS
 
 type App struct {
-	ctx         context.Context
-	watchedDirs map[string]struct{}
-	mu          sync.Mutex
+	ctx          context.Context
+	watchedDirs  map[string]struct{}
+	mu           sync.Mutex
+	sessionTimer *time.Timer
 }
 
 func NewApp() *App {
@@ -33,10 +37,23 @@ func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 	a.watchedDirs = make(map[string]struct{})
 
-	sendAppInitEvent()
+	removeUpdateLeftovers()
+	registerDefaultGameHistoryProviders(a)
+	go a.watchReplayFolders()
+	go a.prefetchSteamProfiles()
+
+	go a.sendAppInitEvent()
+}
+
+func (a *App) shutdown(ctx context.Context) {
+	a.applyStagedUpdate()
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "diagnose" {
+		os.Exit(runDiagnoseCommand(os.Args[2:]))
+	}
+
 	app := NewApp()
 
 	err := wails.Run(&options.App{
@@ -44,10 +61,12 @@ func main() {
 		Width:  1600,
 		Height: 1024,
 		AssetServer: &assetserver.Options{
-			Assets: assets,
+			Assets:  assets,
+			Handler: avatarHandler{},
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
 		Bind: []interface{}{
 			app,
 		},
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	scoutingBookExtension     = ".wranotes"
+	scoutingBookFormatVersion = 1
+)
+
+// Ways to resolve an imported note whose ID exists locally with different
+// content.
+const (
+	NoteConflictKeepLocal    = "keepLocal"
+	NoteConflictKeepImported = "keepImported"
+	NoteConflictNewest       = "newest"
+	NoteConflictKeepBoth     = "keepBoth"
+)
+
+// ScoutingBook is the portable file holding exported notes and favorites.
+type ScoutingBook struct {
+	FormatVersion int                  `json:"formatVersion"`
+	AppVersion    string               `json:"appVersion"`
+	ExportedAt    time.Time            `json:"exportedAt"`
+	Players       []ScoutingBookPlayer `json:"players"`
+}
+
+type ScoutingBookPlayer struct {
+	PlayerId string       `json:"playerId"`
+	Favorite bool         `json:"favorite,omitempty"`
+	Notes    []PlayerNote `json:"notes,omitempty"`
+}
+
+// NotesExportFilter limits an export to some players and to notes carrying at
+// least one of Tags. Empty fields export everything.
+type NotesExportFilter struct {
+	PlayerIds []string `json:"playerIds,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+type NotesImportResult struct {
+	Players        int `json:"players"`
+	Added          int `json:"added"`
+	Updated        int `json:"updated"`
+	Copied         int `json:"copied"`
+	Unchanged      int `json:"unchanged"`
+	KeptLocal      int `json:"keptLocal"`
+	FavoritesAdded int `json:"favoritesAdded"`
+}
+
+func listNotePlayerIds() ([]string, error) {
+	notesDir, err := getLocalAppDataDir("warno-replays-analyser", "playerNotes")
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(notesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ids []string
+	for _, file := range files {
+		if !file.IsDir() && filepath.Ext(file.Name()) == ".json" {
+			ids = append(ids, strings.TrimSuffix(file.Name(), ".json"))
+		}
+	}
+	return ids, nil
+}
+
+func noteHasAnyTag(note PlayerNote, tags []string) bool {
+	for _, tag := range note.Tags {
+		if containsString(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func buildScoutingBook(filter NotesExportFilter, favorites []string) (ScoutingBook, error) {
+	book := ScoutingBook{
+		FormatVersion: scoutingBookFormatVersion,
+		AppVersion:    version,
+		ExportedAt:    time.Now().UTC(),
+		Players:       []ScoutingBookPlayer{},
+	}
+	tags := normalizeTags(filter.Tags)
+
+	noteIds, err := listNotePlayerIds()
+	if err != nil {
+		return book, fmt.Errorf("listing notes: %w", err)
+	}
+
+	players := make(map[string]*ScoutingBookPlayer)
+	include := func(playerId string) *ScoutingBookPlayer {
+		if len(filter.PlayerIds) > 0 && !containsString(filter.PlayerIds, playerId) {
+			return nil
+		}
+		if p, ok := players[playerId]; ok {
+			return p
+		}
+		players[playerId] = &ScoutingBookPlayer{PlayerId: playerId}
+		return players[playerId]
+	}
+
+	for _, playerId := range noteIds {
+		if len(filter.PlayerIds) > 0 && !containsString(filter.PlayerIds, playerId) {
+			continue
+		}
+
+		unlock := lockPlayerNotes(playerId)
+		filePath, err := getNotesFilePath(playerId)
+		var notes []PlayerNote
+		if err == nil {
+			notes, err = loadPlayerNotes(filePath)
+		}
+		unlock()
+		if err != nil {
+			return book, fmt.Errorf("reading notes of %s: %w", playerId, err)
+		}
+
+		for _, note := range notes {
+			if len(tags) > 0 && !noteHasAnyTag(note, tags) {
+				continue
+			}
+			p := include(playerId)
+			p.Notes = append(p.Notes, note)
+		}
+	}
+
+	// With a tag filter, favorites only travel along with matching notes.
+	for _, playerId := range favorites {
+		if _, ok := players[playerId]; len(tags) > 0 && !ok {
+			continue
+		}
+		if p := include(playerId); p != nil {
+			p.Favorite = true
+		}
+	}
+
+	for _, playerId := range sortedMapKeys(players) {
+		book.Players = append(book.Players, *players[playerId])
+	}
+
+	return book, nil
+}
+
+func readScoutingBook(path string) (ScoutingBook, error) {
+	var book ScoutingBook
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return book, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	if err := json.Unmarshal(data, &book); err != nil {
+		return book, fmt.Errorf("unmarshaling %s: %w", filepath.Base(path), err)
+	}
+	if book.FormatVersion > scoutingBookFormatVersion {
+		return book, fmt.Errorf("notes file format %d is newer than supported (%d)", book.FormatVersion, scoutingBookFormatVersion)
+	}
+
+	return book, nil
+}
+
+func sameNoteContent(a, b PlayerNote) bool {
+	return a.Content == b.Content && a.ReplayId == b.ReplayId && reflect.DeepEqual(normalizeTags(a.Tags), normalizeTags(b.Tags))
+}
+
+func hasNoteContent(notes []PlayerNote, note PlayerNote) bool {
+	for _, n := range notes {
+		if sameNoteContent(n, note) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergePlayerNotes merges imported notes into local ones by ID. Imported notes
+// that would be added as new ones are skipped when a local note already has
+// the same content and tags, so importing a file twice adds nothing.
+func mergePlayerNotes(local, imported []PlayerNote, strategy string, result *NotesImportResult) []PlayerNote {
+	index := make(map[string]int, len(local))
+	for i, note := range local {
+		index[note.ID] = i
+	}
+
+	for _, note := range imported {
+		note.Tags = normalizeTags(note.Tags)
+
+		i, exists := index[note.ID]
+		if !exists || note.ID == "" {
+			if hasNoteContent(local, note) {
+				result.Unchanged++
+				continue
+			}
+			if note.ID == "" {
+				note.ID = uuid.New().String()
+			}
+			index[note.ID] = len(local)
+			local = append(local, note)
+			result.Added++
+			continue
+		}
+
+		if sameNoteContent(local[i], note) {
+			result.Unchanged++
+			continue
+		}
+
+		switch strategy {
+		case NoteConflictKeepImported:
+			local[i] = note
+			result.Updated++
+		case NoteConflictNewest:
+			if noteTimestamp(note) > noteTimestamp(local[i]) {
+				local[i] = note
+				result.Updated++
+			} else {
+				result.KeptLocal++
+			}
+		case NoteConflictKeepBoth:
+			if hasNoteContent(local, note) {
+				result.Unchanged++
+				continue
+			}
+			note.ID = uuid.New().String()
+			local = append(local, note)
+			result.Copied++
+		default:
+			result.KeptLocal++
+		}
+	}
+
+	return local
+}
+
+func (a *App) importScoutingBook(book ScoutingBook, strategy string) (NotesImportResult, error) {
+	var result NotesImportResult
+	var newFavorites []string
+
+	for _, p := range book.Players {
+		// Player IDs become file names; skip anything that is not a plain name.
+		if p.PlayerId == "" || sanitizeFileName(p.PlayerId) != p.PlayerId || strings.HasPrefix(p.PlayerId, ".") {
+			log.Printf("Skipping imported notes with invalid player ID %q", p.PlayerId)
+			continue
+		}
+		result.Players++
+		if p.Favorite {
+			newFavorites = append(newFavorites, p.PlayerId)
+		}
+		if len(p.Notes) == 0 {
+			continue
+		}
+
+		err := func() error {
+			unlock := lockPlayerNotes(p.PlayerId)
+			defer unlock()
+
+			filePath, err := getNotesFilePath(p.PlayerId)
+			if err != nil {
+				return err
+			}
+			local, err := loadPlayerNotes(filePath)
+			if err != nil {
+				return err
+			}
+
+			merged := mergePlayerNotes(local, p.Notes, strategy, &result)
+			sort.SliceStable(merged, func(i, j int) bool { return merged[i].CreatedAt < merged[j].CreatedAt })
+
+			return savePlayerNotes(filePath, merged)
+		}()
+		if err != nil {
+			return result, fmt.Errorf("merging notes of %s: %w", p.PlayerId, err)
+		}
+	}
+
+	if len(newFavorites) > 0 {
+		settings, err := a.GetSettings()
+		if err != nil {
+			return result, err
+		}
+		for _, playerId := range newFavorites {
+			// Favorites are only accepted for players the app knows.
+			if !containsString(settings.FavoritePlayerIds, playerId) && !knownPlayerId(playerId) {
+				log.Printf("Skipping imported favorite %s, the player is unknown here", playerId)
+				continue
+			}
+			if !containsString(settings.FavoritePlayerIds, playerId) {
+				settings.FavoritePlayerIds = append(settings.FavoritePlayerIds, playerId)
+				result.FavoritesAdded++
+			}
+		}
+		if result.FavoritesAdded > 0 {
+			if err := a.SaveSettings(settings); err != nil {
+				return result, fmt.Errorf("saving favorites: %w", err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ExportPlayerNotes writes the notes and favorites matching filter to a file
+// picked by the user and returns its path, or "" when cancelled.
+func (a *App) ExportPlayerNotes(filter NotesExportFilter) (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export notes",
+		DefaultFilename: "scouting-book" + scoutingBookExtension,
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Notes (*" + scoutingBookExtension + ")", Pattern: "*" + scoutingBookExtension},
+		},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	settings, err := a.GetSettings()
+	if err != nil {
+		return "", err
+	}
+
+	book, err := buildScoutingBook(filter, settings.FavoritePlayerIds)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(book, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling notes: %w", err)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return "", err
+	}
+
+	log.Printf("Exported notes of %d players to %s", len(book.Players), path)
+
+	return path, nil
+}
+
+// ImportPlayerNotes lets the user pick an exported notes file and merges it
+// into the local notes and favorites. Notes are matched by ID; strategy
+// decides what happens when both sides edited the same note.
+func (a *App) ImportPlayerNotes(strategy string) (NotesImportResult, error) {
+	switch strategy {
+	case "":
+		strategy = NoteConflictNewest
+	case NoteConflictKeepLocal, NoteConflictKeepImported, NoteConflictNewest, NoteConflictKeepBoth:
+	default:
+		return NotesImportResult{}, errors.New("unknown conflict strategy: " + strategy)
+	}
+
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Import notes",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Notes (*" + scoutingBookExtension + ")", Pattern: "*" + scoutingBookExtension},
+		},
+	})
+	if err != nil || path == "" {
+		return NotesImportResult{}, err
+	}
+
+	book, err := readScoutingBook(path)
+	if err != nil {
+		return NotesImportResult{}, err
+	}
+
+	result, err := a.importScoutingBook(book, strategy)
+	if err != nil {
+		return result, err
+	}
+
+	log.Printf("Imported notes from %s: added=%d updated=%d copied=%d kept=%d favorites=%d",
+		path, result.Added, result.Updated, result.Copied, result.KeptLocal, result.FavoritesAdded)
+
+	return result, nil
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"fmt"
-	"io"
-	"net/http"
-	"regexp"
+	"log"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -20,202 +22,288 @@ type PlayerGame struct {
 	PlayerEloChange string   `json:"playerEloChange"`
 	EnemyEloChange  string   `json:"enemyEloChange"`
 	Result          string   `json:"result"`
+	Source          string   `json:"source"`
 }
 
-func (a *App) GetPlayerGameHistory(playerId string) []PlayerGame {
-	return []PlayerGame{} // TODO: Remove when API is available again
+// GameHistoryProvider is a source of a player's match history. Providers
+// return at most limit games, newest first; a limit of 0 leaves the amount
+// up to the provider.
+type GameHistoryProvider interface {
+	Name() string
+	FetchGameHistory(playerId string, limit int) ([]PlayerGame, error)
+}
 
-	url := fmt.Sprintf("https://api.eugnet.com/gamehistory/list?userid=%v&page=0", playerId)
-	fmt.Printf("Fetching URL: %s\n", url)
-	resp, err := http.Get(url)
-	if err != nil {
-		fmt.Printf("Error fetching eugnet api: %v\n", err)
-		return nil
-	}
-	defer resp.Body.Close()
+var (
+	gameHistoryProvidersMu sync.Mutex
+	gameHistoryProviders   []GameHistoryProvider
+)
 
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Received non-OK HTTP status: %s\n", resp.Status)
-		return nil
+// registerGameHistoryProvider adds p to the providers queried by
+// GetPlayerGameHistory, replacing a provider with the same name. Providers
+// registered first win when several return the same game.
+func registerGameHistoryProvider(p GameHistoryProvider) {
+	gameHistoryProvidersMu.Lock()
+	defer gameHistoryProvidersMu.Unlock()
+
+	for i, existing := range gameHistoryProviders {
+		if existing.Name() == p.Name() {
+			gameHistoryProviders[i] = p
+			return
+		}
 	}
+	gameHistoryProviders = append(gameHistoryProviders, p)
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Printf("Error reading response body: %v\n", err)
-		return nil
+// registerDefaultGameHistoryProviders registers the built-in providers, local
+// replays first so their richer records win over the other sources.
+func registerDefaultGameHistoryProviders(a *App) {
+	registerGameHistoryProvider(localReplayHistoryProvider{app: a})
+	registerGameHistoryProvider(remoteReplayHistoryProvider{})
+	if eugnetApiUrl != "" {
+		registerGameHistoryProvider(newEugnetHistoryProvider(eugnetApiUrl))
 	}
+}
 
-	entries := extractGameEntries(string(body), 10)
-	fmt.Printf("Extracted %d entries\n", len(entries))
-
-	if len(entries) == 0 {
-		fmt.Println("No more entries found, stopping.")
-		return nil
-	}
+func registeredGameHistoryProviders() []GameHistoryProvider {
+	gameHistoryProvidersMu.Lock()
+	defer gameHistoryProvidersMu.Unlock()
 
-	for i := range entries {
-		if entries[i].Player != "" && entries[i].Enemy != "" &&
-			len(entries[i].PlayerElo) == 2 && len(entries[i].EnemyElo) == 2 {
-			fmt.Printf("Skipping game details fetch for GameID %s (Player/Enemy already set)\n", entries[i].GameID)
-			continue
-		}
+	return append([]GameHistoryProvider(nil), gameHistoryProviders...)
+}
 
-		gameID := entries[i].GameID
-		url := fmt.Sprintf("https://api.eugnet.com/gamehistory/game?gameid=%s", gameID)
+// GetPlayerGameHistory returns the games of a player from every registered
+// provider, newest first. A game returned by several providers is listed once.
+func (a *App) GetPlayerGameHistory(playerId string) []PlayerGame {
+	games := []PlayerGame{}
+	seen := make(map[string]struct{})
 
-		fmt.Printf("Fetching game details for GameID: %s\n", gameID)
-		resp, err := http.Get(url)
+	for _, p := range registeredGameHistoryProviders() {
+		history, err := p.FetchGameHistory(playerId, 0)
 		if err != nil {
-			fmt.Printf("Error fetching game details for GameID %s: %v\n", gameID, err)
-			continue
+			log.Printf("Error fetching game history for %s from %s: %v", playerId, p.Name(), err)
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
-			fmt.Printf("Received non-OK HTTP status for GameID %s: %s\n", gameID, resp.Status)
-			continue
-		}
+		for _, game := range history {
+			if _, dup := seen[game.GameID]; dup && game.GameID != "" {
+				continue
+			}
+			seen[game.GameID] = struct{}{}
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			fmt.Printf("Error reading game details response for GameID %s: %v\n", gameID, err)
-			continue
+			if game.Source == "" {
+				game.Source = p.Name()
+			}
+			games = append(games, game)
 		}
+	}
 
-		playerName, enemyName, playerElo, enemyElo, playerEloChange, enemyEloChange := extractPlayerAndEnemyData(string(body), entries[i].Result)
+	sort.SliceStable(games, func(i, j int) bool {
+		return parseGameTime(games[i].Date).After(parseGameTime(games[j].Date))
+	})
 
-		if playerName != "" && enemyName != "" {
-			entries[i].Player = playerName
-			entries[i].Enemy = enemyName
-			entries[i].PlayerElo = []string{
-				playerElo,
-				addStrings(playerElo, playerEloChange),
-			}
-			entries[i].EnemyElo = []string{
-				enemyElo,
-				addStrings(enemyElo, enemyEloChange),
-			}
-			entries[i].PlayerEloChange = playerEloChange
-			entries[i].EnemyEloChange = enemyEloChange
-		}
+	return games
+}
+
+// localReplayHistoryProvider reads the games from the replays in the app's
+// replay folders. Elo after the latest game comes from the player's last
+// stored Eugen stats.
+type localReplayHistoryProvider struct {
+	app *App
+}
+
+func (p localReplayHistoryProvider) Name() string { return ReplaySourceLocal }
+
+func (p localReplayHistoryProvider) FetchGameHistory(playerId string, limit int) ([]PlayerGame, error) {
+	records := buildPlayerGameRecords(getReplays(p.app.replayDirectories()), playerId, latestEugenElo(playerId))
+
+	games := make([]PlayerGame, 0, len(records))
+	for i := len(records) - 1; i >= 0 && (limit <= 0 || len(games) < limit); i-- {
+		games = append(games, records[i].game)
 	}
 
-	return entries
+	return games, nil
 }
 
-func extractGameEntries(data string, max int) []PlayerGame {
-	var entries []PlayerGame
+// remoteReplayHistoryProvider lists the replays the remote API stores for the
+// player. Only the session and date are known for those.
+type remoteReplayHistoryProvider struct{}
 
-	gameIDRe := regexp.MustCompile(`gameid=([a-f0-9]{32})`)
-	scoreRe := regexp.MustCompile(`\(\d+/\d+(?: (-?\d*\.\d+))?\)`)
-	timeRe := regexp.MustCompile(`myDate=new Date\((\d+\.\d+)\)`)
-	fuldaRe := regexp.MustCompile(`fulda`)
+func (remoteReplayHistoryProvider) Name() string { return ReplaySourceRemote }
 
-	fuldas := fuldaRe.FindAllStringSubmatch(data, -1)
-	gameIDs := gameIDRe.FindAllStringSubmatch(data, -1)
-	scores := scoreRe.FindAllStringSubmatch(data, -1)
-	times := timeRe.FindAllStringSubmatch(data, -1)
+func (remoteReplayHistoryProvider) FetchGameHistory(playerId string, limit int) ([]PlayerGame, error) {
+	replays, err := syncPlayerReplays(playerId)
 
-	fmt.Printf("Found %d gameids, %d scores, and %d times\n", len(gameIDs), len(scores), len(times))
+	sort.SliceStable(replays, func(i, j int) bool {
+		return replays[i].CreatedAt.After(replays[j].CreatedAt)
+	})
 
-	for i := 0; i < len(gameIDs) && i < max; i++ {
-		if i >= len(scores) || scores[i][1] == "" {
-			continue
+	games := []PlayerGame{}
+	for _, r := range replays {
+		if limit > 0 && len(games) >= limit {
+			break
 		}
-
-		if len(fuldas) > 0 && len(fuldas[i]) > 1 && fuldas[i][1] == "" {
-			fmt.Printf("Skipping game %s due to 'fulda' match\n", gameIDs[i][1])
+		if r.EugenId == "" {
 			continue
 		}
+		games = append(games, PlayerGame{
+			GameID: r.EugenId,
+			Date:   r.CreatedAt.Format(time.RFC3339),
+		})
+	}
 
-		score := scores[i][1]
-		dateStr := ""
-		if i < len(times) {
-			timestamp, err := parseTimestamp(times[i][1])
-			if err == nil {
-				dateStr = timestamp
-			} else {
-				fmt.Printf("Error parsing timestamp: %v\n", err)
-			}
-		}
+	return games, err
+}
 
-		result := "victory"
-		if score[0] == '-' {
-			result = "defeat"
-		}
+type eloPoint struct {
+	playedAt time.Time
+	elo      float64
+}
 
-		entry := PlayerGame{
-			GameID: gameIDs[i][1],
-			Score:  score,
-			Result: result,
-			Date:   dateStr,
-		}
+// playerGameRecord is a local game seen from one player's side.
+type playerGameRecord struct {
+	game           PlayerGame
+	playedAt       time.Time
+	outcome        int
+	eloBefore      float64
+	eloAfter       float64
+	enemyID        string
+	enemyEloBefore float64
+	deck           string
+	enemyDeck      string
+	duration       int
+	mapName        string
+}
 
-		entries = append(entries, entry)
+// parseGameTime reads an RFC3339 game date. Dates that cannot be read are
+// the zero time, so they sort as the oldest.
+func parseGameTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
 	}
+	return t
+}
 
-	return entries
+func parseElo(value string) float64 {
+	elo, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || elo <= 0 {
+		return 0
+	}
+	return elo
 }
 
-func extractPlayerAndEnemyData(data string, result string) (playerName, enemyName, playerElo, enemyElo, playerEloChange, enemyEloChange string) {
-	playerNameRe := regexp.MustCompile(`<span class="name">(.*?)</span>`)
-	eloChangeRe := regexp.MustCompile(`<td>([-+]?[\d]+)</td>`)
+func formatElo(elo float64) string {
+	if elo <= 0 {
+		return ""
+	}
+	return strconv.Itoa(int(math.Round(elo)))
+}
 
-	playerMatches := playerNameRe.FindAllStringSubmatch(data, -1)
-	eloChangeMatches := eloChangeRe.FindAllStringSubmatch(data, -1)
+func formatEloChange(before, after float64) string {
+	if before <= 0 || after <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+d", int(math.Round(after))-int(math.Round(before)))
+}
 
-	if len(playerMatches) < 2 || len(eloChangeMatches) < 4 {
-		return "", "", "", "", "", ""
+// eloAfter returns the Elo a player started their next known game with, which
+// is the Elo they finished the game at playedAt with.
+func eloAfter(timeline []eloPoint, playedAt time.Time) float64 {
+	i := sort.Search(len(timeline), func(i int) bool {
+		return timeline[i].playedAt.After(playedAt)
+	})
+	if i < len(timeline) {
+		return timeline[i].elo
 	}
+	return 0
+}
+
+// buildPlayerGameRecords turns the replays playerId took part in into games
+// seen from that player's side, oldest first. Replays of the same session are
+// counted once. currentElo, when known, closes the Elo change of the latest
+// game.
+func buildPlayerGameRecords(replays []WarnoData, playerId string, currentElo float64) []playerGameRecord {
+	sort.SliceStable(replays, func(i, j int) bool {
+		return parseGameTime(replays[i].CreatedAt).Before(parseGameTime(replays[j].CreatedAt))
+	})
+
+	timelines := make(map[string][]eloPoint)
+	seen := make(map[string]struct{})
+	var records []playerGameRecord
+
+	for _, replay := range replays {
+		sessionID := replay.Warno.Game.UniqueSessionId
+		if _, dup := seen[sessionID]; dup && sessionID != "" {
+			continue
+		}
+		seen[sessionID] = struct{}{}
 
-	player1Name := playerMatches[0][1]
-	player2Name := playerMatches[1][1]
-
-	player1Elo := eloChangeMatches[2][1]
-	player1EloChange := eloChangeMatches[3][1]
-	player2Elo := eloChangeMatches[0][1]
-	player2EloChange := eloChangeMatches[1][1]
-
-	if result == "victory" {
-		if player1EloChange[0] == '-' {
-			playerName, enemyName = player1Name, player2Name
-			playerElo, enemyElo = player2Elo, player1Elo
-			playerEloChange, enemyEloChange = player2EloChange, player1EloChange
-		} else {
-			playerName, enemyName = player2Name, player1Name
-			playerElo, enemyElo = player1Elo, player2Elo
-			playerEloChange, enemyEloChange = player1EloChange, player2EloChange
+		playedAt, err := time.Parse(time.RFC3339, replay.CreatedAt)
+		if err != nil {
+			continue
 		}
-	} else if result == "defeat" {
-		if player1EloChange[0] == '+' {
-			playerName, enemyName = player1Name, player2Name
-			playerElo, enemyElo = player2Elo, player1Elo
-			playerEloChange, enemyEloChange = player2EloChange, player1EloChange
-		} else {
-			playerName, enemyName = player2Name, player1Name
-			playerElo, enemyElo = player1Elo, player2Elo
-			playerEloChange, enemyEloChange = player1EloChange, player2EloChange
+
+		for _, p := range replay.Warno.Players {
+			if elo := parseElo(p.PlayerElo); elo > 0 {
+				timelines[p.PlayerUserId] = append(timelines[p.PlayerUserId], eloPoint{playedAt: playedAt, elo: elo})
+			}
 		}
-	}
 
-	return playerName, enemyName, playerElo, enemyElo, playerEloChange, enemyEloChange
-}
+		var player, enemy Player
+		var playerKey string
+		for key, p := range replay.Warno.Players {
+			if p.PlayerUserId == playerId {
+				player, playerKey = p, key
+			} else {
+				enemy = p
+			}
+		}
+		if playerKey == "" {
+			continue
+		}
 
-func parseTimestamp(timestampStr string) (string, error) {
-	timestamp, err := strconv.ParseFloat(timestampStr, 64)
-	if err != nil {
-		return "", err
+		outcome := replayOutcome(replay.Warno.Result.Victory)
+		if playerKey != replay.Warno.LocalPlayerKey {
+			outcome = -outcome
+		}
+
+		duration, _ := strconv.Atoi(replay.Warno.Result.Duration)
+
+		records = append(records, playerGameRecord{
+			game: PlayerGame{
+				GameID: sessionID,
+				Date:   playedAt.Format(time.RFC3339),
+				Player: player.PlayerName,
+				Enemy:  enemy.PlayerName,
+				Result: resultName(outcome),
+				Source: ReplaySourceLocal,
+			},
+			playedAt:       playedAt,
+			outcome:        outcome,
+			eloBefore:      parseElo(player.PlayerElo),
+			enemyID:        enemy.PlayerUserId,
+			enemyEloBefore: parseElo(enemy.PlayerElo),
+			deck:           player.PlayerDeckContent,
+			enemyDeck:      enemy.PlayerDeckContent,
+			duration:       duration,
+			mapName:        replay.Warno.Game.Map,
+		})
 	}
 
-	t := time.Unix(int64(timestamp/1000), 0)
-	return t.Format(time.RFC3339), nil
-}
+	for i := range records {
+		r := &records[i]
+
+		r.eloAfter = eloAfter(timelines[playerId], r.playedAt)
+		if r.eloAfter == 0 && i == len(records)-1 {
+			r.eloAfter = currentElo
+		}
+		r.game.PlayerElo = []string{formatElo(r.eloBefore), formatElo(r.eloAfter)}
+		r.game.PlayerEloChange = formatEloChange(r.eloBefore, r.eloAfter)
+		r.game.Score = r.game.PlayerEloChange
 
-func addStrings(base, delta string) string {
-	baseInt, err1 := strconv.Atoi(base)
-	deltaInt, err2 := strconv.Atoi(delta)
-	if err1 != nil || err2 != nil {
-		return base
+		enemyAfter := eloAfter(timelines[r.enemyID], r.playedAt)
+		r.game.EnemyElo = []string{formatElo(r.enemyEloBefore), formatElo(enemyAfter)}
+		r.game.EnemyEloChange = formatEloChange(r.enemyEloBefore, enemyAfter)
 	}
-	return strconv.Itoa(baseInt + deltaInt)
+
+	return records
 }
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	playerNameCacheTTL = 24 * time.Hour
+	// playerNameMissingTTL is how long an ID the player API does not know is
+	// left alone before it is asked for again.
+	playerNameMissingTTL = 6 * time.Hour
+)
+
+// cachedPlayerName holds the last name the API returned, if any. MissingAt is
+// the last time the API did not know the ID; the name is kept in that case.
+type cachedPlayerName struct {
+	Name      string    `json:"name,omitempty"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	MissingAt time.Time `json:"missingAt"`
+}
+
+func (e cachedPlayerName) fresh() bool {
+	return (e.Name != "" && time.Since(e.FetchedAt) <= playerNameCacheTTL) ||
+		time.Since(e.MissingAt) <= playerNameMissingTTL
+}
+
+// playerNameCache maps Eugen IDs to the usernames known by the player API.
+// Expired entries are kept so they can still be shown when the API is down.
+type playerNameCache struct {
+	mu      sync.Mutex
+	loaded  bool
+	entries map[int]cachedPlayerName
+}
+
+var playerNames = &playerNameCache{}
+
+func getPlayerNameCacheFilePath() (string, error) {
+	dir, err := getLocalAppDataDir("warno-replays-analyser")
+	if err != nil {
+		return "", fmt.Errorf("failed to get app data directory: %w", err)
+	}
+	return filepath.Join(dir, "playerNameCache.json"), nil
+}
+
+func (c *playerNameCache) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	c.entries = make(map[int]cachedPlayerName)
+
+	filePath, err := getPlayerNameCacheFilePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		log.Printf("Error reading player name cache, starting empty: %v", err)
+		c.entries = make(map[int]cachedPlayerName)
+	}
+}
+
+func (c *playerNameCache) save() error {
+	filePath, err := getPlayerNameCacheFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("marshaling player name cache: %w", err)
+	}
+	return writeFileAtomic(filePath, data, 0644)
+}
+
+// lookup splits ids into names known to the cache and IDs whose entry is
+// missing or expired. IDs the API recently did not know are not stale.
+func (c *playerNameCache) lookup(ids []int) (map[int]string, []int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	names := make(map[int]string, len(ids))
+	var stale []int
+	for _, id := range ids {
+		entry, ok := c.entries[id]
+		if ok && entry.Name != "" {
+			names[id] = entry.Name
+		}
+		if !ok || !entry.fresh() {
+			stale = append(stale, id)
+		}
+	}
+
+	return names, stale
+}
+
+// known reports whether id was looked up, which happens for every player
+// shown on the leaderboard.
+func (c *playerNameCache) known(id int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	_, ok := c.entries[id]
+	return ok
+}
+
+// store records fetched names. An empty name marks the ID as unknown to the
+// API without dropping a name cached before.
+func (c *playerNameCache) store(names map[int]string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	now := time.Now().UTC()
+	for id, name := range names {
+		if name == "" {
+			entry := c.entries[id]
+			entry.MissingAt = now
+			c.entries[id] = entry
+			continue
+		}
+		c.entries[id] = cachedPlayerName{Name: name, FetchedAt: now}
+	}
+
+	return c.save()
+}
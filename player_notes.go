@@ -3,19 +3,25 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// PlayerNote is a note about a player. Tags are lowercase; ReplayId links the
+// note to a game by its UniqueSessionId.
 type PlayerNote struct {
-	ID        string `json:"id"`
-	Content   string `json:"content"`
-	CreatedAt string `json:"createdAt"`
+	ID        string   `json:"id"`
+	Content   string   `json:"content"`
+	CreatedAt string   `json:"createdAt"`
+	UpdatedAt string   `json:"updatedAt,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	ReplayId  string   `json:"replayId,omitempty"`
 }
 
 func getNotesFilePath(playerId string) (string, error) {
@@ -31,28 +37,96 @@ func getNotesFilePath(playerId string) (string, error) {
 	return filepath.Join(notesDir, fmt.Sprintf("%s.json", playerId)), nil
 }
 
-func loadPlayerNotes(filePath string) ([]PlayerNote, error) {
+// notesBackupCount is the number of previous versions kept next to a notes
+// file as <file>.bak.1 (newest) to <file>.bak.N.
+const notesBackupCount = 3
+
+var playerNotesLocks sync.Map
+
+// lockPlayerNotes serialises access to one player's notes file and returns
+// the unlock function.
+func lockPlayerNotes(playerId string) func() {
+	mu, _ := playerNotesLocks.LoadOrStore(playerId, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+func notesBackupPath(filePath string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", filePath, n)
+}
+
+func decodePlayerNotes(data []byte) ([]PlayerNote, error) {
 	var notes []PlayerNote
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return nil, fmt.Errorf("unmarshaling JSON: %w", err)
+	}
+	return notes, nil
+}
 
-	f, err := os.Open(filePath)
+// loadPlayerNotes reads a notes file. A corrupt file is moved aside and the
+// newest readable backup is restored in its place.
+func loadPlayerNotes(filePath string) ([]PlayerNote, error) {
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return notes, nil
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	notes, err := decodePlayerNotes(data)
+	if err == nil {
+		return notes, nil
+	}
+
+	return recoverPlayerNotes(filePath, err)
+}
+
+func recoverPlayerNotes(filePath string, cause error) ([]PlayerNote, error) {
+	corruptPath := fmt.Sprintf("%s.corrupt-%d", filePath, time.Now().Unix())
+	if err := os.Rename(filePath, corruptPath); err != nil {
+		return nil, fmt.Errorf("notes file is corrupt (%v) and could not be moved aside: %w", cause, err)
+	}
+
+	for n := 1; n <= notesBackupCount; n++ {
+		data, err := os.ReadFile(notesBackupPath(filePath, n))
+		if err != nil {
+			continue
 		}
-		return nil, fmt.Errorf("opening file: %w", err)
+		notes, err := decodePlayerNotes(data)
+		if err != nil {
+			continue
+		}
+
+		if err := writeFileAtomic(filePath, data, 0644); err != nil {
+			return nil, fmt.Errorf("restoring notes from backup %d: %w", n, err)
+		}
+		log.Printf("Notes file %s was corrupt (%v); restored backup %d, corrupt copy kept at %s", filePath, cause, n, corruptPath)
+		return notes, nil
 	}
 
-	defer f.Close()
+	return nil, fmt.Errorf("notes file is corrupt and no backup could be read, corrupt copy kept at %s: %w", corruptPath, cause)
+}
 
-	data, err := io.ReadAll(f)
+// rotateNoteBackups shifts the existing backups by one and copies the current
+// notes file into the first slot.
+func rotateNoteBackups(filePath string) error {
+	current, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("reading file: %w", err)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading current notes: %w", err)
 	}
-	if err := json.Unmarshal(data, &notes); err != nil {
-		return nil, fmt.Errorf("unmarshaling JSON: %w", err)
+
+	for n := notesBackupCount; n > 1; n-- {
+		err := os.Rename(notesBackupPath(filePath, n-1), notesBackupPath(filePath, n))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotating backup %d: %w", n-1, err)
+		}
 	}
 
-	return notes, nil
+	return writeFileAtomic(notesBackupPath(filePath, 1), current, 0644)
 }
 
 func savePlayerNotes(filePath string, notes []PlayerNote) error {
@@ -61,55 +135,104 @@ func savePlayerNotes(filePath string, notes []PlayerNote) error {
 		return fmt.Errorf("marshaling JSON: %w", err)
 	}
 
-	f, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("creating file: %w", err)
+	if err := rotateNoteBackups(filePath); err != nil {
+		log.Printf("Error backing up %s: %v", filePath, err)
 	}
-	defer f.Close()
 
-	if _, err := f.Write(data); err != nil {
-		return fmt.Errorf("writing to file: %w", err)
-	}
+	return writeFileAtomic(filePath, data, 0644)
+}
 
-	return nil
+// normalizeTags lowercases and trims tags, dropping empty and repeated ones.
+func normalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if _, dup := seen[tag]; dup || tag == "" {
+			continue
+		}
+		seen[tag] = struct{}{}
+		out = append(out, tag)
+	}
+	return out
 }
 
-func (a *App) CreatePlayerNote(playerId, content string) {
+func (a *App) CreatePlayerNote(playerId, content string, tags []string, replayId string) (PlayerNote, error) {
+	unlock := lockPlayerNotes(playerId)
+	defer unlock()
+
 	filePath, err := getNotesFilePath(playerId)
 	if err != nil {
-		log.Printf("Error: %v", err)
-		return
+		return PlayerNote{}, err
 	}
 
 	notes, err := loadPlayerNotes(filePath)
 	if err != nil {
-		log.Printf("Error: %v", err)
-		return
+		return PlayerNote{}, err
 	}
 
 	newNote := PlayerNote{
 		ID:        uuid.New().String(),
 		Content:   content,
 		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Tags:      normalizeTags(tags),
+		ReplayId:  strings.TrimSpace(replayId),
 	}
 	notes = append(notes, newNote)
 
 	if err := savePlayerNotes(filePath, notes); err != nil {
-		log.Printf("Error: %v", err)
+		return PlayerNote{}, err
+	}
+
+	return newNote, nil
+}
+
+// UpdatePlayerNote replaces the content, tags and replay link of a note.
+func (a *App) UpdatePlayerNote(playerId, noteId, content string, tags []string, replayId string) (PlayerNote, error) {
+	unlock := lockPlayerNotes(playerId)
+	defer unlock()
+
+	filePath, err := getNotesFilePath(playerId)
+	if err != nil {
+		return PlayerNote{}, err
+	}
+
+	notes, err := loadPlayerNotes(filePath)
+	if err != nil {
+		return PlayerNote{}, err
 	}
+
+	for i := range notes {
+		if notes[i].ID != noteId {
+			continue
+		}
+
+		notes[i].Content = content
+		notes[i].Tags = normalizeTags(tags)
+		notes[i].ReplayId = strings.TrimSpace(replayId)
+		notes[i].UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+		if err := savePlayerNotes(filePath, notes); err != nil {
+			return PlayerNote{}, err
+		}
+		return notes[i], nil
+	}
+
+	return PlayerNote{}, fmt.Errorf("note %s not found for player %s", noteId, playerId)
 }
 
-func (a *App) DeletePlayerNote(playerId, noteId string) {
+func (a *App) DeletePlayerNote(playerId, noteId string) error {
+	unlock := lockPlayerNotes(playerId)
+	defer unlock()
+
 	filePath, err := getNotesFilePath(playerId)
 	if err != nil {
-		log.Printf("Error: %v", err)
-		return
+		return err
 	}
 
 	notes, err := loadPlayerNotes(filePath)
 	if err != nil {
-		log.Printf("Error: %v", err)
-		return
+		return err
 	}
 
 	filtered := notes[:0]
@@ -119,25 +242,27 @@ func (a *App) DeletePlayerNote(playerId, noteId string) {
 		}
 	}
 
-	if err := savePlayerNotes(filePath, filtered); err != nil {
-		log.Printf("Error: %v", err)
-	}
+	return savePlayerNotes(filePath, filtered)
 }
 
-func (a *App) GetPlayerNotes(playerId string) string {
+// GetPlayerNotes returns the notes of a player. Unreadable notes are reported
+// as an error rather than an empty list.
+func (a *App) GetPlayerNotes(playerId string) ([]PlayerNote, error) {
+	unlock := lockPlayerNotes(playerId)
+	defer unlock()
+
 	filePath, err := getNotesFilePath(playerId)
 	if err != nil {
-		log.Printf("Error: %v", err)
-		return "[]"
+		return nil, err
 	}
 
-	data, err := os.ReadFile(filePath)
+	notes, err := loadPlayerNotes(filePath)
 	if err != nil {
-		if !os.IsNotExist(err) {
-			log.Printf("Error reading notes: %v", err)
-		}
-		return "[]"
+		return nil, fmt.Errorf("reading notes of %s: %w", playerId, err)
+	}
+	if notes == nil {
+		return []PlayerNote{}, nil
 	}
 
-	return string(data)
+	return notes, nil
 }
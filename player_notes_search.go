@@ -0,0 +1,176 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const noteSearchLimit = 100
+
+// Weights of the ways a query term can match a note.
+const (
+	noteTagWeight    = 5.0
+	noteWordWeight   = 2.0
+	notePrefixWeight = 1.0
+	notePartWeight   = 0.5
+	notePhraseWeight = 3.0
+	notePlayerWeight = 4.0
+)
+
+// PlayerNoteSearchResult is a matching note. PlayerName is the player's
+// current name when the player appears in the local replays.
+type PlayerNoteSearchResult struct {
+	PlayerId   string     `json:"playerId"`
+	PlayerName string     `json:"playerName,omitempty"`
+	Note       PlayerNote `json:"note"`
+	Score      float64    `json:"score"`
+}
+
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// noteTermScore scores one query term against a note; 0 means no match.
+func noteTermScore(term string, words, tags []string, content string) float64 {
+	var score float64
+
+	for _, tag := range tags {
+		if tag == term {
+			score += noteTagWeight
+		} else if strings.HasPrefix(tag, term) {
+			score += notePrefixWeight
+		}
+	}
+
+	for _, word := range words {
+		if word == term {
+			score += noteWordWeight
+		} else if strings.HasPrefix(word, term) {
+			score += notePrefixWeight
+		}
+	}
+
+	if score == 0 && strings.Contains(content, term) {
+		score = notePartWeight
+	}
+
+	return score
+}
+
+// scorePlayerNote returns how well note matches the query terms. Every term
+// has to match the content or a tag.
+func scorePlayerNote(query string, terms []string, note PlayerNote) float64 {
+	content := strings.ToLower(note.Content)
+	words := splitWords(note.Content)
+
+	var score float64
+	for _, term := range terms {
+		s := noteTermScore(term, words, note.Tags, content)
+		if s == 0 {
+			return 0
+		}
+		score += s
+	}
+
+	if len(terms) > 1 {
+		if strings.Contains(content, query) {
+			score += notePhraseWeight
+		}
+		for _, tag := range note.Tags {
+			if tag == query {
+				score += noteTagWeight
+			}
+		}
+	}
+
+	return score
+}
+
+func noteTimestamp(note PlayerNote) string {
+	if note.UpdatedAt != "" {
+		return note.UpdatedAt
+	}
+	return note.CreatedAt
+}
+
+// SearchPlayerNotes searches the notes of all players, best matches first.
+// Terms may be prefixed with # to look for tags, and the query may name the
+// player by any name they used; ties go to the most recently edited note.
+func (a *App) SearchPlayerNotes(query string) []PlayerNoteSearchResult {
+	results := []PlayerNoteSearchResult{}
+
+	query = strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(query, "#", " "))), " ")
+	if query == "" {
+		return results
+	}
+	terms := strings.Fields(query)
+
+	notesDir, err := getLocalAppDataDir("warno-replays-analyser", "playerNotes")
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return results
+	}
+
+	files, err := os.ReadDir(notesDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Error listing notes: %v", err)
+		}
+		return results
+	}
+
+	var playerIds []string
+	for _, file := range files {
+		if !file.IsDir() && filepath.Ext(file.Name()) == ".json" {
+			playerIds = append(playerIds, strings.TrimSuffix(file.Name(), ".json"))
+		}
+	}
+	identities := localIdentities.resolve(playerIds)
+
+	for _, playerId := range playerIds {
+		identity, known := identities[playerId]
+		nameMatches := known && identityMatchScore(identity, query) > 0
+
+		unlock := lockPlayerNotes(playerId)
+		notes, err := loadPlayerNotes(filepath.Join(notesDir, playerId+".json"))
+		unlock()
+		if err != nil {
+			log.Printf("Error reading notes of %s: %v", playerId, err)
+			continue
+		}
+
+		for _, note := range notes {
+			score := scorePlayerNote(query, terms, note)
+			if nameMatches {
+				score += notePlayerWeight
+			}
+			if score > 0 {
+				results = append(results, PlayerNoteSearchResult{
+					PlayerId:   playerId,
+					PlayerName: identity.CanonicalName,
+					Note:       note,
+					Score:      score,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return noteTimestamp(results[i].Note) > noteTimestamp(results[j].Note)
+	})
+
+	if len(results) > noteSearchLimit {
+		results = results[:noteSearchLimit]
+	}
+
+	return results
+}
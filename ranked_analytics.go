@@ -0,0 +1,122 @@
+package main
+
+import (
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rankedAnalyticsCacheTTL = 5 * time.Minute
+
+// RankedReplaysAnalyticsFilter narrows down the global ranked-replay analytics.
+// Zero values leave the corresponding filter off. From and To accept either a
+// date (2006-01-02) or an RFC3339 timestamp; MinDuration is in seconds.
+// SortBy orders the rows locally and is not sent to the API.
+type RankedReplaysAnalyticsFilter struct {
+	MaxRank     int      `json:"maxRank"`
+	MinElo      int      `json:"minElo"`
+	From        string   `json:"from,omitempty"`
+	To          string   `json:"to,omitempty"`
+	Versions    []string `json:"versions,omitempty"`
+	Maps        []string `json:"maps,omitempty"`
+	Divisions   []int    `json:"divisions,omitempty"`
+	MinDuration int      `json:"minDuration,omitempty"`
+	SortBy      string   `json:"sortBy,omitempty"`
+}
+
+type rankedAnalyticsCacheEntry struct {
+	fetchedAt time.Time
+	response  RankedReplaysAnalyticsResponse
+}
+
+var (
+	rankedAnalyticsCacheMu sync.Mutex
+	rankedAnalyticsCache   = make(map[string]rankedAnalyticsCacheEntry)
+)
+
+func parseFilterTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// encode writes the filter into q. Invalid dates are logged and skipped.
+func (f RankedReplaysAnalyticsFilter) encode(q url.Values) {
+	if f.MaxRank > 0 {
+		q.Set("maxRank", strconv.Itoa(f.MaxRank))
+	}
+	if f.MinElo > 0 {
+		q.Set("minElo", strconv.Itoa(f.MinElo))
+	}
+	if f.From != "" {
+		if t, err := parseFilterTime(f.From); err == nil {
+			q.Set("from", t.UTC().Format(time.RFC3339))
+		} else {
+			log.Printf("Ignoring invalid analytics filter from=%q: %v", f.From, err)
+		}
+	}
+	if f.To != "" {
+		if t, err := parseFilterTime(f.To); err == nil {
+			q.Set("to", t.UTC().Format(time.RFC3339))
+		} else {
+			log.Printf("Ignoring invalid analytics filter to=%q: %v", f.To, err)
+		}
+	}
+	for _, v := range f.Versions {
+		if v = strings.TrimSpace(v); v != "" {
+			q.Add("version", v)
+		}
+	}
+	for _, m := range f.Maps {
+		if m = strings.TrimSpace(m); m != "" {
+			q.Add("map", m)
+		}
+	}
+	for _, d := range f.Divisions {
+		if d > 0 {
+			q.Add("division", strconv.Itoa(d))
+		}
+	}
+	if f.MinDuration > 0 {
+		q.Set("minDuration", strconv.Itoa(f.MinDuration))
+	}
+}
+
+func getCachedRankedAnalytics(key string) (RankedReplaysAnalyticsResponse, bool) {
+	rankedAnalyticsCacheMu.Lock()
+	defer rankedAnalyticsCacheMu.Unlock()
+
+	entry, ok := rankedAnalyticsCache[key]
+	if !ok {
+		return RankedReplaysAnalyticsResponse{}, false
+	}
+	if time.Since(entry.fetchedAt) > rankedAnalyticsCacheTTL {
+		delete(rankedAnalyticsCache, key)
+		return RankedReplaysAnalyticsResponse{}, false
+	}
+
+	return entry.response, true
+}
+
+func storeCachedRankedAnalytics(key string, response RankedReplaysAnalyticsResponse) {
+	rankedAnalyticsCacheMu.Lock()
+	defer rankedAnalyticsCacheMu.Unlock()
+
+	rankedAnalyticsCache[key] = rankedAnalyticsCacheEntry{
+		fetchedAt: time.Now(),
+		response:  response,
+	}
+}
+
+// ClearRankedReplaysAnalyticsCache drops every cached analytics response so the
+// next request goes to the remote API.
+func (a *App) ClearRankedReplaysAnalyticsCache() {
+	rankedAnalyticsCacheMu.Lock()
+	defer rankedAnalyticsCacheMu.Unlock()
+
+	rankedAnalyticsCache = make(map[string]rankedAnalyticsCacheEntry)
+}
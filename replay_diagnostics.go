@@ -0,0 +1,320 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// Replay diagnostic statuses.
+const (
+	replayParsed        = "parsed"
+	replayFiltered      = "filtered"
+	replayTruncated     = "truncated"
+	replayCorruptHeader = "corrupt_header"
+	replayUnknownFormat = "unknown_format"
+)
+
+// RecoveredPlayer is what the game header tells about a player.
+type RecoveredPlayer struct {
+	Name     string `json:"name"`
+	EugenId  string `json:"eugenId"`
+	Elo      string `json:"elo,omitempty"`
+	Alliance string `json:"alliance"`
+}
+
+// ReplayHeaderInfo is read from the game header alone, so it is available
+// for truncated replays too.
+type ReplayHeaderInfo struct {
+	SessionId string            `json:"sessionId,omitempty"`
+	Map       string            `json:"map,omitempty"`
+	GameMode  string            `json:"gameMode,omitempty"`
+	Version   string            `json:"version,omitempty"`
+	Players   []RecoveredPlayer `json:"players"`
+}
+
+type ReplayDiagnostic struct {
+	Path       string            `json:"path"`
+	Status     string            `json:"status"`
+	Reason     string            `json:"reason,omitempty"`
+	Size       int64             `json:"size"`
+	ModifiedAt string            `json:"modifiedAt,omitempty"`
+	Header     *ReplayHeaderInfo `json:"header,omitempty"`
+}
+
+type ReplayDiagnosticsReport struct {
+	GeneratedAt string             `json:"generatedAt"`
+	Folders     []string           `json:"folders"`
+	Counts      map[string]int     `json:"counts"`
+	Replays     []ReplayDiagnostic `json:"replays"`
+}
+
+// replayContentSkipReason returns why processFile skips a replay based on its
+// raw content, or "" when it does not.
+func replayContentSkipReason(content string) string {
+	if !strings.Contains(content, `"NbMaxPlayer":"2"`) {
+		return "not a 1v1 game"
+	}
+	if !strings.Contains(content, `"IsNetworkMode":"1"`) {
+		return "not an online game"
+	}
+	return ""
+}
+
+// replayLobbySkipReason returns why processFile skips a replay based on its
+// game header, or "" when it does not. Hosted games are custom lobbies.
+func replayLobbySkipReason(game map[string]any) string {
+	if _, exists := game["WithHost"]; exists {
+		return "custom lobby game"
+	}
+	if _, exists := game["ServerName"]; exists {
+		return "custom lobby game"
+	}
+	return ""
+}
+
+func recoverReplayHeader(header map[string]any) *ReplayHeaderInfo {
+	info := &ReplayHeaderInfo{Players: []RecoveredPlayer{}}
+
+	if gameAny, ok := header["game"]; ok {
+		gameData, _ := json.Marshal(gameAny)
+		var game Game
+		if json.Unmarshal(gameData, &game) == nil {
+			info.SessionId = game.UniqueSessionId
+			info.Map = game.Map
+			info.GameMode = game.GameMode
+			info.Version = game.Version
+		}
+	}
+
+	for _, key := range sortedMapKeys(header) {
+		if !strings.HasPrefix(key, "player_") {
+			continue
+		}
+		playerData, _ := json.Marshal(header[key])
+		var p Player
+		if json.Unmarshal(playerData, &p) != nil {
+			continue
+		}
+		info.Players = append(info.Players, RecoveredPlayer{
+			Name:     p.PlayerName,
+			EugenId:  p.PlayerUserId,
+			Elo:      p.PlayerElo,
+			Alliance: p.PlayerAlliance,
+		})
+	}
+
+	return info
+}
+
+// diagnoseReplay runs the parsing steps of processFile one at a time and
+// reports the first one that fails.
+func diagnoseReplay(path string) ReplayDiagnostic {
+	d := ReplayDiagnostic{Path: path}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		d.Status, d.Reason = replayUnknownFormat, fmt.Sprintf("cannot read file: %v", err)
+		return d
+	}
+	d.Size = info.Size()
+	d.ModifiedAt = info.ModTime().Format(time.RFC3339)
+
+	content, err := readFileContent(path)
+	if err != nil {
+		d.Status, d.Reason = replayUnknownFormat, fmt.Sprintf("cannot read file: %v", err)
+		return d
+	}
+	cleaned := strings.ReplaceAll(content, "\n", "")
+
+	if !strings.Contains(cleaned, `{"game":`) {
+		d.Status, d.Reason = replayUnknownFormat, "no embedded game header"
+		return d
+	}
+
+	var header map[string]any
+	headerMatch := gameRegex.FindString(cleaned)
+	if headerMatch == "" {
+		d.Status, d.Reason = replayCorruptHeader, "game header is incomplete"
+		return d
+	}
+	if err := json.Unmarshal([]byte(headerMatch), &header); err != nil {
+		d.Status, d.Reason = replayCorruptHeader, fmt.Sprintf("game header is not valid JSON: %v", err)
+		return d
+	}
+	d.Header = recoverReplayHeader(header)
+
+	if reason := replayContentSkipReason(content); reason != "" {
+		d.Status, d.Reason = replayFiltered, reason
+		return d
+	}
+	game, ok := header["game"].(map[string]any)
+	if !ok {
+		d.Status, d.Reason = replayCorruptHeader, "game header has no game settings"
+		return d
+	}
+	if reason := replayLobbySkipReason(game); reason != "" {
+		d.Status, d.Reason = replayFiltered, reason
+		return d
+	}
+
+	resultMatch := resultRegex.FindString(cleaned)
+	if resultMatch == "" {
+		d.Status, d.Reason = replayTruncated, "no result block, the game crashed or was left before it ended"
+		return d
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(resultMatch), &result); err != nil {
+		d.Status, d.Reason = replayTruncated, fmt.Sprintf("result block is not valid JSON: %v", err)
+		return d
+	}
+
+	if _, err := mergeJsons(path, []map[string]any{header, result}, info); err != nil {
+		d.Status, d.Reason = replayCorruptHeader, err.Error()
+		return d
+	}
+
+	d.Status = replayParsed
+	return d
+}
+
+func diagnoseReplayFolders(folders []string) ReplayDiagnosticsReport {
+	paths := listReplayFiles(folders)
+	diagnostics := make([]ReplayDiagnostic, len(paths))
+
+	var wg sync.WaitGroup
+	indexes := make(chan int, 100)
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				diagnostics[i] = diagnoseReplay(paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	report := ReplayDiagnosticsReport{
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		Folders:     folders,
+		Counts:      make(map[string]int),
+		Replays:     diagnostics,
+	}
+	for _, d := range diagnostics {
+		report.Counts[d.Status]++
+	}
+	sort.Slice(report.Replays, func(i, j int) bool {
+		return report.Replays[i].Path < report.Replays[j].Path
+	})
+
+	return report
+}
+
+// DiagnoseReplays checks every replay in directories, or in all known replay
+// folders when none are given, and reports which ones cannot be used and why.
+func (a *App) DiagnoseReplays(directories []string) ReplayDiagnosticsReport {
+	if len(directories) == 0 {
+		directories = a.replayDirectories()
+	}
+	return diagnoseReplayFolders(directories)
+}
+
+// attachParentConsole lets the GUI executable print to the console it was
+// started from.
+func attachParentConsole() {
+	const attachParentProcess = ^uintptr(0)
+	attach := windows.NewLazySystemDLL("kernel32.dll").NewProc("AttachConsole")
+	if r, _, _ := attach.Call(attachParentProcess); r == 0 {
+		return
+	}
+	if out, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0); err == nil {
+		os.Stdout, os.Stderr = out, out
+	}
+}
+
+func writeDiagnosticsText(w io.Writer, report ReplayDiagnosticsReport) {
+	for _, d := range report.Replays {
+		if d.Status == replayParsed {
+			continue
+		}
+		fmt.Fprintf(w, "%-15s %s\n", d.Status, d.Path)
+		if d.Reason != "" {
+			fmt.Fprintf(w, "%15s %s\n", "", d.Reason)
+		}
+		if d.Header != nil {
+			var players []string
+			for _, p := range d.Header.Players {
+				players = append(players, fmt.Sprintf("%s (%s)", p.Name, p.EugenId))
+			}
+			fmt.Fprintf(w, "%15s map %s, players %s\n", "", d.Header.Map, strings.Join(players, ", "))
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d replays in %d folders\n", len(report.Replays), len(report.Folders))
+	for _, status := range []string{replayParsed, replayFiltered, replayTruncated, replayCorruptHeader, replayUnknownFormat} {
+		fmt.Fprintf(w, "%-15s %d\n", status, report.Counts[status])
+	}
+}
+
+// runDiagnoseCommand implements "warno-replays-analyser diagnose [-json]
+// [-out file] [folder...]".
+func runDiagnoseCommand(args []string) int {
+	attachParentConsole()
+
+	fs := flag.NewFlagSet("diagnose", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	outPath := fs.String("out", "", "write the report to `file` instead of the console")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	folders := fs.Args()
+	for i, f := range folders {
+		if abs, err := filepath.Abs(f); err == nil {
+			folders[i] = abs
+		}
+	}
+	if len(folders) == 0 {
+		folders = NewApp().replayDirectories()
+	}
+	report := diagnoseReplayFolders(folders)
+
+	var w io.Writer = os.Stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	writeDiagnosticsText(w, report)
+	return 0
+}
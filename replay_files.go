@@ -0,0 +1,444 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"golang.org/x/sys/windows"
+)
+
+const defaultRenameTemplate = "{date}_{me}_vs_{enemy}_{map}_{result}"
+
+// selfChangeWindow is how long watcher events for files the app renamed,
+// moved or deleted itself are ignored.
+const selfChangeWindow = 5 * time.Second
+
+// ReplayFileChange reports what happened to one replay of a bulk operation.
+// NewPath is empty for deleted replays and Error is set when it failed.
+type ReplayFileChange struct {
+	OldPath string `json:"oldPath"`
+	NewPath string `json:"newPath,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// selfChanges holds the paths the app itself just changed, so the watcher
+// does not report them as new replays.
+var selfChanges sync.Map
+
+func markSelfChange(paths ...string) {
+	until := time.Now().Add(selfChangeWindow)
+	for _, p := range paths {
+		selfChanges.Store(strings.ToLower(filepath.Clean(p)), until)
+	}
+}
+
+func isSelfChange(path string) bool {
+	key := strings.ToLower(filepath.Clean(path))
+	until, ok := selfChanges.Load(key)
+	if !ok {
+		return false
+	}
+	if time.Now().After(until.(time.Time)) {
+		selfChanges.Delete(key)
+		return false
+	}
+	return true
+}
+
+func replayCachePath(replayPath string) (string, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, filepath.Base(replayPath)+".json"), nil
+}
+
+// moveReplayCache renames the cache entry of a moved replay and updates the
+// names it holds, so the replay is not parsed again.
+func moveReplayCache(oldPath, newPath string) {
+	oldCache, err := replayCachePath(oldPath)
+	if err != nil {
+		return
+	}
+	newCache, err := replayCachePath(newPath)
+	if err != nil {
+		return
+	}
+
+	data, err := os.ReadFile(oldCache)
+	if err != nil {
+		return
+	}
+	os.Remove(oldCache)
+
+	var cached cachedReplay
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return
+	}
+	if cached.Data != nil {
+		cached.Data.FileName = filepath.Base(newPath)
+		cached.Data.Key = cached.Data.FileName
+		cached.Data.FilePath = newPath
+	}
+
+	encoded, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	if err := writeFileAtomic(newCache, encoded, 0644); err != nil {
+		log.Printf("Error moving cache of %s: %v", oldPath, err)
+	}
+}
+
+func removeReplayCache(replayPath string) {
+	if cachePath, err := replayCachePath(replayPath); err == nil {
+		os.Remove(cachePath)
+	}
+}
+
+// replayLabel fills the placeholders of template for replay: {date}, {me},
+// {enemy}, {map} and {result}.
+func replayLabel(template string, replay WarnoData) string {
+	me := replay.Warno.Players[replay.Warno.LocalPlayerKey]
+	var enemy Player
+	for key, p := range replay.Warno.Players {
+		if key != replay.Warno.LocalPlayerKey && p.PlayerAlliance != me.PlayerAlliance {
+			enemy = p
+		}
+	}
+
+	date := replay.CreatedAt
+	if t, err := time.Parse(time.RFC3339, replay.CreatedAt); err == nil {
+		date = t.Local().Format("2006-01-02_15-04")
+	}
+
+	r := strings.NewReplacer(
+		"{date}", date,
+		"{me}", me.PlayerName,
+		"{enemy}", enemy.PlayerName,
+		"{map}", strings.TrimPrefix(replay.Warno.Game.Map, "_"),
+		"{result}", resultName(replayOutcome(replay.Warno.Result.Victory)),
+	)
+	return sanitizeFileName(r.Replace(template))
+}
+
+// freePath returns path, or path with " (n)" before the extension when a file
+// already exists there.
+func freePath(path string) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = fmt.Sprintf("%s (%d)%s", base, n, ext)
+	}
+}
+
+// crossDevice reports whether a rename failed only because src and dst are on
+// different volumes.
+func crossDevice(err error) bool {
+	return errors.Is(err, windows.ERROR_NOT_SAME_DEVICE) || errors.Is(err, syscall.EXDEV)
+}
+
+// moveFile renames src to dst, copying across volumes. The modification time
+// is kept since it is the replay's date. Any other rename error, like the game
+// still holding the replay open, is returned as is.
+func moveFile(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !crossDevice(err) {
+		return err
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	if err := os.Chtimes(dst, info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+
+	in.Close()
+	// Leave a single copy behind when the original cannot be removed.
+	if err := os.Remove(src); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+// moveReplay moves a replay and keeps the cache and identity index in step.
+func moveReplay(oldPath, newPath string) error {
+	markSelfChange(oldPath, newPath)
+	if err := moveFile(oldPath, newPath); err != nil {
+		return err
+	}
+
+	moveReplayCache(oldPath, newPath)
+	if err := localIdentities.moved(oldPath, newPath); err != nil {
+		log.Printf("Error updating identity index: %v", err)
+	}
+	return nil
+}
+
+// replaysByPath parses the given replays; paths that are not ranked replays
+// are missing from the result.
+func replaysByPath(paths []string) map[string]WarnoData {
+	byPath := make(map[string]WarnoData, len(paths))
+	for _, replay := range parseReplayFiles(paths) {
+		byPath[strings.ToLower(filepath.Clean(replay.FilePath))] = replay
+	}
+	return byPath
+}
+
+func (a *App) emitReplayFilesChanged(changes []ReplayFileChange) {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "replay-files-changed", changes)
+	}
+}
+
+// RenameReplays renames replays after template, or after
+// defaultRenameTemplate when it is empty. Replays stay in their folder and
+// existing files are never overwritten.
+func (a *App) RenameReplays(paths []string, template string) []ReplayFileChange {
+	if strings.TrimSpace(template) == "" {
+		template = defaultRenameTemplate
+	}
+
+	byPath := replaysByPath(paths)
+	changes := make([]ReplayFileChange, 0, len(paths))
+
+	for _, path := range paths {
+		change := ReplayFileChange{OldPath: path}
+
+		replay, ok := byPath[strings.ToLower(filepath.Clean(path))]
+		if !ok {
+			change.Error = "not a ranked replay"
+			changes = append(changes, change)
+			continue
+		}
+
+		name := replayLabel(template, replay) + filepath.Ext(path)
+		if strings.EqualFold(name, filepath.Base(path)) {
+			change.NewPath = path
+			changes = append(changes, change)
+			continue
+		}
+
+		newPath := freePath(filepath.Join(filepath.Dir(path), name))
+		if err := moveReplay(path, newPath); err != nil {
+			change.Error = err.Error()
+		} else {
+			change.NewPath = newPath
+		}
+		changes = append(changes, change)
+	}
+
+	a.emitReplayFilesChanged(changes)
+	return changes
+}
+
+// ArchiveReplays moves replays into folder, or into an "archive" folder next
+// to each replay when folder is empty. Archived replays are no longer listed
+// unless the archive is added as a replay folder.
+func (a *App) ArchiveReplays(paths []string, folder string) []ReplayFileChange {
+	changes := make([]ReplayFileChange, 0, len(paths))
+
+	for _, path := range paths {
+		change := ReplayFileChange{OldPath: path}
+
+		dir := folder
+		if dir == "" {
+			dir = filepath.Join(filepath.Dir(path), "archive")
+		}
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			change.Error = err.Error()
+			changes = append(changes, change)
+			continue
+		}
+
+		newPath := freePath(filepath.Join(dir, filepath.Base(path)))
+		if err := moveReplay(path, newPath); err != nil {
+			change.Error = err.Error()
+		} else {
+			change.NewPath = newPath
+		}
+		changes = append(changes, change)
+	}
+
+	a.emitReplayFilesChanged(changes)
+	return changes
+}
+
+// SelectArchiveFolder asks the user for a folder to archive replays into and
+// returns its path, or "" when cancelled.
+func (a *App) SelectArchiveFolder() (string, error) {
+	return runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:                "Archive replays to",
+		CanCreateDirectories: true,
+	})
+}
+
+// DeleteReplays deletes replays after the user confirms. It returns no
+// changes when the user cancels.
+func (a *App) DeleteReplays(paths []string) ([]ReplayFileChange, error) {
+	if len(paths) == 0 {
+		return []ReplayFileChange{}, nil
+	}
+
+	answer, err := runtime.MessageDialog(a.ctx, runtime.MessageDialogOptions{
+		Type:          runtime.QuestionDialog,
+		Title:         "Delete replays",
+		Message:       fmt.Sprintf("Delete %d replay(s)? This cannot be undone.", len(paths)),
+		Buttons:       []string{"Delete", "Cancel"},
+		DefaultButton: "Cancel",
+		CancelButton:  "Cancel",
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Windows shows Yes/No whatever the buttons.
+	if answer != "Delete" && answer != "Yes" {
+		return []ReplayFileChange{}, nil
+	}
+
+	changes := make([]ReplayFileChange, 0, len(paths))
+	for _, path := range paths {
+		change := ReplayFileChange{OldPath: path}
+
+		markSelfChange(path)
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			change.Error = err.Error()
+		} else {
+			removeReplayCache(path)
+			if err := localIdentities.removed(path); err != nil {
+				log.Printf("Error updating identity index: %v", err)
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	a.emitReplayFilesChanged(changes)
+	return changes, nil
+}
+
+// replayTagStore keeps user tags per game, keyed by UniqueSessionId so they
+// survive renames and moves and apply to every copy of a replay.
+type replayTagStore struct {
+	mu     sync.Mutex
+	loaded bool
+	tags   map[string][]string
+}
+
+var replayTags = &replayTagStore{}
+
+func getReplayTagsFilePath() (string, error) {
+	// Outside the versioned cache folder so tags survive app updates.
+	dir, err := getLocalAppDataDir("warno-replays-analyser", "cache")
+	if err != nil {
+		return "", fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	return filepath.Join(dir, "replayTags.json"), nil
+}
+
+func (s *replayTagStore) load() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	s.tags = make(map[string][]string)
+
+	filePath, err := getReplayTagsFilePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &s.tags); err != nil {
+		log.Printf("Error reading replay tags: %v", err)
+	}
+}
+
+func (s *replayTagStore) all() map[string][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load()
+
+	out := make(map[string][]string, len(s.tags))
+	for id, tags := range s.tags {
+		out[id] = append([]string(nil), tags...)
+	}
+	return out
+}
+
+func (s *replayTagStore) set(sessionId string, tags []string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load()
+
+	tags = normalizeTags(tags)
+	if len(tags) == 0 {
+		delete(s.tags, sessionId)
+	} else {
+		s.tags[sessionId] = tags
+	}
+
+	filePath, err := getReplayTagsFilePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(s.tags)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling replay tags: %w", err)
+	}
+	if err := writeFileAtomic(filePath, data, 0644); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// GetReplayTags returns the tags of all games keyed by UniqueSessionId.
+func (a *App) GetReplayTags() map[string][]string {
+	return replayTags.all()
+}
+
+// SetReplayTags replaces the tags of a game; no tags removes the entry.
+func (a *App) SetReplayTags(sessionId string, tags []string) ([]string, error) {
+	sessionId = strings.TrimSpace(sessionId)
+	if sessionId == "" {
+		return nil, errors.New("session ID is required")
+	}
+	return replayTags.set(sessionId, tags)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ReplayFolder is a folder of replays outside the Steam userdata folders, such
+// as downloaded tournament replays or a shared drive. Disabled folders are
+// kept in the settings but not scanned.
+type ReplayFolder struct {
+	Path    string `json:"path"`
+	Label   string `json:"label,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+func sameFolder(a, b string) bool {
+	return strings.EqualFold(filepath.Clean(a), filepath.Clean(b))
+}
+
+func (s Settings) replayFolderIndex(path string) int {
+	for i, f := range s.ReplayFolders {
+		if sameFolder(f.Path, path) {
+			return i
+		}
+	}
+	return -1
+}
+
+// enabledReplayFolders returns the paths of the enabled custom folders.
+func (s Settings) enabledReplayFolders() []string {
+	var paths []string
+	for _, f := range s.ReplayFolders {
+		if f.Enabled {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// replayFolderSources returns the Steam save folders keyed by account name and
+// the enabled custom folders keyed by label.
+func (a *App) replayFolderSources() map[string]string {
+	sources, err := findWarnoSaveFolders()
+	if err != nil {
+		log.Println("Error:", err)
+	}
+	if sources == nil {
+		sources = make(map[string]string)
+	}
+
+	settings, err := a.GetSettings()
+	if err != nil {
+		log.Printf("Error loading settings: %v", err)
+		return sources
+	}
+
+	for _, f := range settings.ReplayFolders {
+		if !f.Enabled {
+			continue
+		}
+		label := f.Label
+		if label == "" {
+			label = filepath.Base(f.Path)
+		}
+		if _, taken := sources[label]; taken {
+			label = fmt.Sprintf("%s (%s)", label, f.Path)
+		}
+		sources[label] = f.Path
+	}
+
+	return sources
+}
+
+// watchDirectory starts watching dir for new replays unless it already is.
+func (a *App) watchDirectory(dir string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, alreadyWatching := a.watchedDirs[dir]; !alreadyWatching {
+		a.watchedDirs[dir] = struct{}{}
+		go a.watchFolder(dir)
+	}
+}
+
+// watchReplayFolders watches every Steam and enabled custom folder.
+func (a *App) watchReplayFolders() {
+	for _, dir := range a.replayFolderSources() {
+		a.watchDirectory(dir)
+	}
+}
+
+func cleanReplayFolderPath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", errors.New("folder path is required")
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot access %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a folder", path)
+	}
+
+	return path, nil
+}
+
+// SelectReplayFolder asks the user for a folder and returns its path, or ""
+// when cancelled.
+func (a *App) SelectReplayFolder() (string, error) {
+	return runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Add replay folder",
+	})
+}
+
+// AddReplayFolder adds an enabled custom folder and starts watching it.
+func (a *App) AddReplayFolder(path, label string) (Settings, error) {
+	path, err := cleanReplayFolderPath(path)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	settings, err := a.GetSettings()
+	if err != nil {
+		return Settings{}, err
+	}
+	if settings.replayFolderIndex(path) >= 0 {
+		return Settings{}, fmt.Errorf("%s is already a replay folder", path)
+	}
+
+	settings.ReplayFolders = append(settings.ReplayFolders, ReplayFolder{
+		Path:    path,
+		Label:   strings.TrimSpace(label),
+		Enabled: true,
+	})
+	if err := a.SaveSettings(settings); err != nil {
+		return Settings{}, err
+	}
+
+	a.watchDirectory(path)
+
+	return settings, nil
+}
+
+// UpdateReplayFolder changes the label of a custom folder and enables or
+// disables it. A disabled folder is no longer scanned but stays watched until
+// the app restarts.
+func (a *App) UpdateReplayFolder(path, label string, enabled bool) (Settings, error) {
+	settings, err := a.GetSettings()
+	if err != nil {
+		return Settings{}, err
+	}
+
+	i := settings.replayFolderIndex(path)
+	if i < 0 {
+		return Settings{}, fmt.Errorf("%s is not a replay folder", path)
+	}
+	settings.ReplayFolders[i].Label = strings.TrimSpace(label)
+	settings.ReplayFolders[i].Enabled = enabled
+
+	if err := a.SaveSettings(settings); err != nil {
+		return Settings{}, err
+	}
+	if enabled {
+		a.watchDirectory(settings.ReplayFolders[i].Path)
+	}
+
+	return settings, nil
+}
+
+func (a *App) RemoveReplayFolder(path string) (Settings, error) {
+	settings, err := a.GetSettings()
+	if err != nil {
+		return Settings{}, err
+	}
+
+	i := settings.replayFolderIndex(path)
+	if i < 0 {
+		return Settings{}, fmt.Errorf("%s is not a replay folder", path)
+	}
+	settings.ReplayFolders = append(settings.ReplayFolders[:i], settings.ReplayFolders[i+1:]...)
+
+	if err := a.SaveSettings(settings); err != nil {
+		return Settings{}, err
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	ReplaySourceRemote = "remote"
+	ReplaySourceLocal  = "local"
+	ReplaySourceBoth   = "both"
+)
+
+type replaySyncState struct {
+	PlayerID      string      `json:"playerId"`
+	LastSyncedAt  time.Time   `json:"lastSyncedAt"`
+	LastCreatedAt time.Time   `json:"lastCreatedAt"`
+	LastID        uint        `json:"lastId"`
+	Replays       []GetReplay `json:"replays"`
+}
+
+type PlayerReplayHistoryEntry struct {
+	SessionID string     `json:"sessionId"`
+	RemoteID  uint       `json:"remoteId,omitempty"`
+	Division  string     `json:"division,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Source    string     `json:"source"`
+	Replay    *WarnoData `json:"replay,omitempty"`
+}
+
+var replaySyncMu sync.Mutex
+
+func getReplaySyncFilePath(playerId string) (string, error) {
+	syncDir, err := getLocalAppDataDir("warno-replays-analyser", "replaySync")
+	if err != nil {
+		return "", fmt.Errorf("getting replaySync directory: %w", err)
+	}
+
+	return filepath.Join(syncDir, sanitizeFileName(playerId)+".json"), nil
+}
+
+func loadReplaySyncState(filePath string) (replaySyncState, error) {
+	var state replaySyncState
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, fmt.Errorf("reading file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return replaySyncState{}, fmt.Errorf("unmarshaling JSON: %w", err)
+	}
+
+	return state, nil
+}
+
+func saveReplaySyncState(filePath string, state replaySyncState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+
+	return writeFileAtomic(filePath, data, 0644)
+}
+
+// fetchPlayerReplays asks the remote API for the replays of a player. When
+// since or afterID are set, only records newer than them are requested.
+func fetchPlayerReplays(id string, since time.Time, afterID uint) ([]GetReplay, error) {
+	if apiUrl == "" || apiKey == "" {
+		return nil, fmt.Errorf("API_URL or API_KEY is not set")
+	}
+
+	parsed, err := url.Parse(fmt.Sprintf("%s/players/%s/replays", apiUrl, url.PathEscape(id)))
+	if err != nil {
+		return nil, fmt.Errorf("parsing url: %w", err)
+	}
+
+	q := parsed.Query()
+	if !since.IsZero() {
+		q.Set("since", since.UTC().Format(time.RFC3339Nano))
+	}
+	if afterID > 0 {
+		q.Set("afterId", strconv.FormatUint(uint64(afterID), 10))
+	}
+	parsed.RawQuery = q.Encode()
+
+	headers := map[string]string{
+		"Authorization": "Bearer " + apiKey,
+	}
+
+	resp, err := makeRequest("GET", parsed.String(), nil, headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-OK HTTP status: %d", resp.StatusCode)
+	}
+
+	var result []GetReplay
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding response body: %w", err)
+	}
+
+	return result, nil
+}
+
+// mergeReplayRecords adds fetched records to the stored ones. Records are keyed
+// by ID, so an API that ignores the since/afterId filters and returns the full
+// list does not produce duplicates.
+func mergeReplayRecords(stored []GetReplay, fetched []GetReplay) []GetReplay {
+	byKey := make(map[string]int, len(stored)+len(fetched))
+	merged := make([]GetReplay, 0, len(stored)+len(fetched))
+
+	key := func(r GetReplay) string {
+		if r.ID != 0 {
+			return "id:" + strconv.FormatUint(uint64(r.ID), 10)
+		}
+		return "eugen:" + r.EugenId + ":" + r.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	for _, replays := range [][]GetReplay{stored, fetched} {
+		for _, r := range replays {
+			k := key(r)
+			if i, exists := byKey[k]; exists {
+				merged[i] = r
+				continue
+			}
+			byKey[k] = len(merged)
+			merged = append(merged, r)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].CreatedAt.Equal(merged[j].CreatedAt) {
+			return merged[i].ID < merged[j].ID
+		}
+		return merged[i].CreatedAt.Before(merged[j].CreatedAt)
+	})
+
+	return merged
+}
+
+// syncPlayerReplays downloads the records newer than the last sync and stores
+// them next to the ones already known. When the API is unreachable the stored
+// records are returned together with the error.
+func syncPlayerReplays(id string) ([]GetReplay, error) {
+	replaySyncMu.Lock()
+	defer replaySyncMu.Unlock()
+
+	filePath, err := getReplaySyncFilePath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := loadReplaySyncState(filePath)
+	if err != nil {
+		log.Printf("Error loading replay sync state for %s, starting a full sync: %v", id, err)
+		state = replaySyncState{}
+	}
+	state.PlayerID = id
+
+	fetched, err := fetchPlayerReplays(id, state.LastCreatedAt, state.LastID)
+	if err != nil {
+		return state.Replays, err
+	}
+
+	state.Replays = mergeReplayRecords(state.Replays, fetched)
+	state.LastSyncedAt = time.Now().UTC()
+	for _, r := range state.Replays {
+		if r.CreatedAt.After(state.LastCreatedAt) {
+			state.LastCreatedAt = r.CreatedAt
+		}
+		if r.ID > state.LastID {
+			state.LastID = r.ID
+		}
+	}
+
+	if err := saveReplaySyncState(filePath, state); err != nil {
+		log.Printf("Error saving replay sync state for %s: %v", id, err)
+	}
+
+	log.Printf("Synced replays for %s: fetched=%d total=%d", id, len(fetched), len(state.Replays))
+
+	return state.Replays, nil
+}
+
+// GetPlayerReplayHistory combines the synced remote records of a player with the
+// local replays from directories in which that player took part. Entries that
+// share a session ID are merged and marked with both sources.
+func (a *App) GetPlayerReplayHistory(id string, directories []string) []PlayerReplayHistoryEntry {
+	remote, err := syncPlayerReplays(id)
+	if err != nil {
+		log.Printf("Error syncing replays for %s: %v", id, err)
+	}
+
+	var history []PlayerReplayHistoryEntry
+	bySession := make(map[string]int)
+
+	for _, r := range remote {
+		if r.EugenId != "" {
+			if _, exists := bySession[r.EugenId]; exists {
+				continue
+			}
+			bySession[r.EugenId] = len(history)
+		}
+		history = append(history, PlayerReplayHistoryEntry{
+			SessionID: r.EugenId,
+			RemoteID:  r.ID,
+			Division:  r.Division,
+			CreatedAt: r.CreatedAt,
+			Source:    ReplaySourceRemote,
+		})
+	}
+
+	for _, replay := range getReplays(directories) {
+		if !replayHasPlayer(replay, id) {
+			continue
+		}
+
+		replay := replay
+		sessionID := replay.Warno.Game.UniqueSessionId
+		if i, exists := bySession[sessionID]; exists && sessionID != "" {
+			if history[i].Source == ReplaySourceRemote {
+				history[i].Source = ReplaySourceBoth
+				history[i].Replay = &replay
+			}
+			continue
+		}
+
+		createdAt, _ := time.Parse(time.RFC3339, replay.CreatedAt)
+		if sessionID != "" {
+			bySession[sessionID] = len(history)
+		}
+		history = append(history, PlayerReplayHistoryEntry{
+			SessionID: sessionID,
+			CreatedAt: createdAt,
+			Source:    ReplaySourceLocal,
+			Replay:    &replay,
+		})
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].CreatedAt.After(history[j].CreatedAt)
+	})
+
+	return history
+}
+
+func replayHasPlayer(replay WarnoData, playerId string) bool {
+	for _, player := range replay.Warno.Players {
+		if player.PlayerUserId == playerId {
+			return true
+		}
+	}
+	return false
+}
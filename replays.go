@@ -4,29 +4,26 @@ import (
 	"log"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 )
 
 func getReplays(directories []string) []WarnoData {
-	var wg sync.WaitGroup
-	result := sync.Map{}
-	fileChan := make(chan string, 100)
-
-	numWorkers := runtime.NumCPU()
+	files := listReplayFiles(directories)
+	replays, processed := processReplayFiles(files)
 
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for filePath := range fileChan {
-				if err := processFile(filePath, &result); err != nil {
-					log.Printf("Error processing file %s: %v", filePath, err)
-				}
-			}
-		}()
+	if err := localIdentities.update(processed, replays); err != nil {
+		log.Printf("Error updating local identities: %v", err)
 	}
 
+	return replays
+}
+
+// listReplayFiles returns the absolute paths of the replays in directories.
+func listReplayFiles(directories []string) []string {
+	var paths []string
+
 	for _, directory := range directories {
 		dir, err := filepath.Abs(directory)
 		if err != nil {
@@ -46,10 +43,51 @@ func getReplays(directories []string) []WarnoData {
 			if file.IsDir() || strings.ToLower(filepath.Ext(file.Name())) != ".rpl3" {
 				continue
 			}
-			fileChan <- filepath.Join(dir, file.Name())
+			paths = append(paths, filepath.Join(dir, file.Name()))
 		}
 	}
 
+	return paths
+}
+
+func parseReplayFiles(paths []string) []WarnoData {
+	replays, _ := processReplayFiles(paths)
+	return replays
+}
+
+// processReplayFiles parses paths and also returns the paths that were
+// processed without error, whether they held a ranked replay or were
+// filtered out. Files that could not be read or parsed, like a replay the
+// game is still writing, are left out.
+func processReplayFiles(paths []string) ([]WarnoData, []string) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var processed []string
+	result := sync.Map{}
+	fileChan := make(chan string, 100)
+
+	numWorkers := runtime.NumCPU()
+
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for filePath := range fileChan {
+				if err := processFile(filePath, &result); err != nil {
+					log.Printf("Error processing file %s: %v", filePath, err)
+					continue
+				}
+				mu.Lock()
+				processed = append(processed, filePath)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, path := range paths {
+		fileChan <- path
+	}
+
 	close(fileChan)
 	wg.Wait()
 
@@ -61,20 +99,76 @@ func getReplays(directories []string) []WarnoData {
 		return true
 	})
 
-	return finalResult
+	return finalResult, processed
 }
 
-func (a *App) GetReplays(directories []string) []WarnoData {
-	for _, dir := range directories {
-		a.mu.Lock()
+// replayDirectories returns the folders the frontend loaded replays from, the
+// active profile's folders, the Steam save folders and the enabled custom
+// folders, without duplicates.
+func (a *App) replayDirectories() []string {
+	seen := make(map[string]struct{})
+	var directories []string
 
-		if _, alreadyWatching := a.watchedDirs[dir]; !alreadyWatching {
-			a.watchedDirs[dir] = struct{}{}
-			go a.watchFolder(dir)
+	add := func(dir string) {
+		if dir == "" {
+			return
+		}
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
 		}
+		if _, exists := seen[strings.ToLower(dir)]; exists {
+			return
+		}
+		seen[strings.ToLower(dir)] = struct{}{}
+		directories = append(directories, dir)
+	}
+
+	a.mu.Lock()
+	for dir := range a.watchedDirs {
+		add(dir)
+	}
+	a.mu.Unlock()
 
-		a.mu.Unlock()
+	for _, dir := range a.activeProfileFolders() {
+		add(dir)
 	}
 
-	return getReplays(directories)
+	for _, dir := range a.replayFolderSources() {
+		add(dir)
+	}
+
+	sort.Strings(directories)
+
+	return directories
+}
+
+// GetReplays loads the replays of directories, or of the active profile's
+// folders when none are given, and keeps watching them for new files. Only
+// replays of the profile's accounts are returned when it names any.
+func (a *App) GetReplays(directories []string) []WarnoData {
+	settings, err := a.GetSettings()
+	if err != nil {
+		log.Printf("Error loading settings: %v", err)
+	}
+	if len(directories) == 0 {
+		directories = settings.Folders
+	}
+
+	for _, dir := range directories {
+		a.watchDirectory(dir)
+	}
+
+	tags := replayTags.all()
+	replays := getReplays(directories)
+
+	filtered := replays[:0]
+	for _, replay := range replays {
+		local := replay.Warno.Players[replay.Warno.LocalPlayerKey]
+		if len(settings.PlayerIds) > 0 && !containsString(settings.PlayerIds, local.PlayerUserId) {
+			continue
+		}
+		replay.Tags = tags[replay.Warno.Game.UniqueSessionId]
+		filtered = append(filtered, replay)
+	}
+	return filtered
 }
@@ -6,16 +6,12 @@ import (
 )
 
 func (a *App) GetWarnoSaveFolders() string {
-	warnoPaths, err := findWarnoSaveFolders()
-	if err != nil {
-		log.Println("Error:", err)
-		return "[]"
-	}
+	warnoPaths := a.replayFolderSources()
 
 	if len(warnoPaths) == 0 {
-		log.Println("No Warno save folders found.")
+		log.Println("No replay folders found.")
 	} else {
-		log.Println("Found Warno save folders:")
+		log.Println("Found replay folders:")
 		for _, path := range warnoPaths {
 			log.Println(path)
 		}
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// sessionGap is the longest break between two games of the same session.
+	sessionGap = 45 * time.Minute
+
+	// sessionUpdateDelay leaves the game time to finish writing a new replay
+	// before the current session is rebuilt.
+	sessionUpdateDelay = 3 * time.Second
+
+	TiltNone    = "none"
+	TiltWarning = "warning"
+	TiltTilted  = "tilted"
+
+	tiltWarningStreak = 2
+	tiltStreak        = 3
+	tiltEloLoss       = 50
+)
+
+// SessionGame is one point of the session streak graph. Streak is the signed
+// length of the running streak after the game: +3 after three wins in a row,
+// -2 after two losses, 0 after a draw.
+type SessionGame struct {
+	GameID    string  `json:"gameId"`
+	PlayedAt  string  `json:"playedAt"`
+	Result    string  `json:"result"`
+	Streak    int     `json:"streak"`
+	Elo       float64 `json:"elo"`
+	EloChange float64 `json:"eloChange"`
+	Deck      string  `json:"deck"`
+	EnemyDeck string  `json:"enemyDeck"`
+	Map       string  `json:"map"`
+}
+
+// SessionDeck counts the games played with one division, whatever changes
+// were made to the deck between games. Deck is the code of the latest game;
+// decks whose division cannot be read are counted per deck code.
+type SessionDeck struct {
+	DivisionId int    `json:"divisionId"`
+	Division   string `json:"division"`
+	Deck       string `json:"deck"`
+	Games      int    `json:"games"`
+	Wins       int    `json:"wins"`
+	Losses     int    `json:"losses"`
+}
+
+type SessionMatchup struct {
+	DivisionId      int     `json:"divisionId"`
+	Division        string  `json:"division"`
+	EnemyDivisionId int     `json:"enemyDivisionId"`
+	EnemyDivision   string  `json:"enemyDivision"`
+	Deck            string  `json:"deck"`
+	EnemyDeck       string  `json:"enemyDeck"`
+	Games           int     `json:"games"`
+	Wins            int     `json:"wins"`
+	Losses          int     `json:"losses"`
+	WinRate         float64 `json:"winRate"`
+}
+
+// deckGroupKey groups deck codes by division, or by code when the division is
+// unknown.
+func deckGroupKey(code string) (key string, divisionId int) {
+	if id := deckDivisionId(code); id > 0 {
+		return fmt.Sprintf("division:%d", id), id
+	}
+	return "deck:" + code, 0
+}
+
+// SessionTilt flags a losing run at the end of a session. Level is "warning"
+// after two straight losses and "tilted" after three, or earlier when the run
+// already cost a lot of Elo.
+type SessionTilt struct {
+	Level        string  `json:"level"`
+	LosingStreak int     `json:"losingStreak"`
+	EloLost      float64 `json:"eloLost"`
+}
+
+// PlaySession is a run of games with no break longer than sessionGap. Active
+// is set while the last game is recent enough for the session to continue.
+type PlaySession struct {
+	ID                     string          `json:"id"`
+	PlayerId               string          `json:"playerId"`
+	StartedAt              string          `json:"startedAt"`
+	EndedAt                string          `json:"endedAt"`
+	Active                 bool            `json:"active"`
+	GamesPlayed            int             `json:"gamesPlayed"`
+	Wins                   int             `json:"wins"`
+	Losses                 int             `json:"losses"`
+	Draws                  int             `json:"draws"`
+	EloStart               float64         `json:"eloStart"`
+	EloEnd                 float64         `json:"eloEnd"`
+	EloChange              float64         `json:"eloChange"`
+	AverageDurationSeconds int             `json:"averageDurationSeconds"`
+	Games                  []SessionGame   `json:"games"`
+	Decks                  []SessionDeck   `json:"decks"`
+	BestMatchup            *SessionMatchup `json:"bestMatchup"`
+	WorstMatchup           *SessionMatchup `json:"worstMatchup"`
+	Tilt                   SessionTilt     `json:"tilt"`
+}
+
+// groupSessions splits records, oldest first, into sessions.
+func groupSessions(records []playerGameRecord) [][]playerGameRecord {
+	var sessions [][]playerGameRecord
+
+	for i, r := range records {
+		if i == 0 || r.playedAt.Sub(records[i-1].playedAt) > sessionGap {
+			sessions = append(sessions, nil)
+		}
+		sessions[len(sessions)-1] = append(sessions[len(sessions)-1], r)
+	}
+
+	return sessions
+}
+
+func resultName(outcome int) string {
+	switch {
+	case outcome > 0:
+		return "victory"
+	case outcome < 0:
+		return "defeat"
+	default:
+		return "draw"
+	}
+}
+
+func buildPlaySession(playerId string, records []playerGameRecord, now time.Time) PlaySession {
+	first, last := records[0], records[len(records)-1]
+
+	session := PlaySession{
+		ID:          first.game.GameID,
+		PlayerId:    playerId,
+		StartedAt:   first.playedAt.Format(time.RFC3339),
+		EndedAt:     last.playedAt.Format(time.RFC3339),
+		Active:      now.Sub(last.playedAt) <= sessionGap,
+		GamesPlayed: len(records),
+		EloStart:    first.eloBefore,
+		EloEnd:      last.eloAfter,
+		Games:       []SessionGame{},
+		Decks:       []SessionDeck{},
+	}
+	if session.EloStart > 0 && session.EloEnd > 0 {
+		session.EloChange = session.EloEnd - session.EloStart
+	}
+
+	decks := make(map[string]*SessionDeck)
+	matchups := make(map[[2]string]*SessionMatchup)
+	var totalDuration, timedGames, streak int
+
+	for _, r := range records {
+		switch {
+		case r.outcome > 0:
+			session.Wins++
+			if streak < 0 {
+				streak = 0
+			}
+			streak++
+		case r.outcome < 0:
+			session.Losses++
+			if streak > 0 {
+				streak = 0
+			}
+			streak--
+		default:
+			session.Draws++
+			streak = 0
+		}
+
+		var eloChange float64
+		if r.eloBefore > 0 && r.eloAfter > 0 {
+			eloChange = r.eloAfter - r.eloBefore
+		}
+		session.Games = append(session.Games, SessionGame{
+			GameID:    r.game.GameID,
+			PlayedAt:  r.game.Date,
+			Result:    resultName(r.outcome),
+			Streak:    streak,
+			Elo:       r.eloAfter,
+			EloChange: eloChange,
+			Deck:      r.deck,
+			EnemyDeck: r.enemyDeck,
+			Map:       r.mapName,
+		})
+
+		if r.duration > 0 {
+			totalDuration += r.duration
+			timedGames++
+		}
+
+		deckKey, divisionId := deckGroupKey(r.deck)
+		enemyKey, enemyDivisionId := deckGroupKey(r.enemyDeck)
+
+		deck, ok := decks[deckKey]
+		if !ok {
+			deck = &SessionDeck{DivisionId: divisionId, Division: divisionName(divisionId)}
+			decks[deckKey] = deck
+		}
+		matchup, ok := matchups[[2]string{deckKey, enemyKey}]
+		if !ok {
+			matchup = &SessionMatchup{
+				DivisionId:      divisionId,
+				Division:        divisionName(divisionId),
+				EnemyDivisionId: enemyDivisionId,
+				EnemyDivision:   divisionName(enemyDivisionId),
+			}
+			matchups[[2]string{deckKey, enemyKey}] = matchup
+		}
+		// Records are oldest first, so the samples end up as the latest decks.
+		deck.Deck = r.deck
+		matchup.Deck, matchup.EnemyDeck = r.deck, r.enemyDeck
+		deck.Games++
+		matchup.Games++
+		switch {
+		case r.outcome > 0:
+			deck.Wins++
+			matchup.Wins++
+		case r.outcome < 0:
+			deck.Losses++
+			matchup.Losses++
+		}
+	}
+
+	if timedGames > 0 {
+		session.AverageDurationSeconds = totalDuration / timedGames
+	}
+
+	for _, key := range sortedMapKeys(decks) {
+		session.Decks = append(session.Decks, *decks[key])
+	}
+	sort.SliceStable(session.Decks, func(i, j int) bool {
+		return session.Decks[i].Games > session.Decks[j].Games
+	})
+
+	for _, key := range sortedMatchupKeys(matchups) {
+		m := matchups[key]
+		m.WinRate = winRate(m.Wins, m.Games)
+		if session.BestMatchup == nil || betterMatchup(m, session.BestMatchup) {
+			session.BestMatchup = m
+		}
+		if session.WorstMatchup == nil || betterMatchup(session.WorstMatchup, m) {
+			session.WorstMatchup = m
+		}
+	}
+	if session.BestMatchup == session.WorstMatchup {
+		session.WorstMatchup = nil
+	}
+
+	session.Tilt = sessionTilt(records)
+
+	return session
+}
+
+func sortedMatchupKeys(m map[[2]string]*SessionMatchup) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// betterMatchup orders matchups by winrate, then by the number of games so
+// that a 3-0 beats a 1-0 and a 0-3 is worse than a 0-1.
+func betterMatchup(a, b *SessionMatchup) bool {
+	if a.WinRate != b.WinRate {
+		return a.WinRate > b.WinRate
+	}
+	if a.WinRate >= 0.5 {
+		return a.Games > b.Games
+	}
+	return a.Games < b.Games
+}
+
+// sessionTilt looks at the losing streak the session ends on.
+func sessionTilt(records []playerGameRecord) SessionTilt {
+	tilt := SessionTilt{Level: TiltNone}
+
+	for i := len(records) - 1; i >= 0 && records[i].outcome < 0; i-- {
+		tilt.LosingStreak++
+		if r := records[i]; r.eloBefore > 0 && r.eloAfter > 0 {
+			tilt.EloLost += r.eloBefore - r.eloAfter
+		}
+	}
+
+	switch {
+	case tilt.LosingStreak >= tiltStreak,
+		tilt.LosingStreak >= tiltWarningStreak && tilt.EloLost >= tiltEloLoss:
+		tilt.Level = TiltTilted
+	case tilt.LosingStreak >= tiltWarningStreak:
+		tilt.Level = TiltWarning
+	}
+
+	return tilt
+}
+
+// sessionPlayer resolves an empty playerId to the DailyRecapUser setting, or
+// to the local player of the most recent replay.
+func (a *App) sessionPlayer(playerId string, replays []WarnoData) string {
+	if playerId != "" {
+		return playerId
+	}
+
+	settings, err := a.GetSettings()
+	if err != nil {
+		log.Printf("Error loading settings: %v", err)
+	}
+	if settings.DailyRecapUser != "" {
+		return settings.DailyRecapUser
+	}
+
+	var latest WarnoData
+	for _, replay := range replays {
+		if replay.CreatedAt > latest.CreatedAt {
+			latest = replay
+		}
+	}
+	return latest.Warno.LocalPlayerEugenId
+}
+
+func (a *App) playSessions(playerId string) []PlaySession {
+	replays := getReplays(a.replayDirectories())
+	playerId = a.sessionPlayer(playerId, replays)
+	if playerId == "" {
+		return nil
+	}
+
+	records := buildPlayerGameRecords(replays, playerId, latestEugenElo(playerId))
+
+	now := time.Now()
+	var sessions []PlaySession
+	for _, group := range groupSessions(records) {
+		sessions = append(sessions, buildPlaySession(playerId, group, now))
+	}
+
+	return sessions
+}
+
+// GetPlaySessions returns the player's sessions, newest first. An empty
+// playerId uses the daily recap player.
+func (a *App) GetPlaySessions(playerId string) []PlaySession {
+	sessions := a.playSessions(playerId)
+
+	out := make([]PlaySession, 0, len(sessions))
+	for i := len(sessions) - 1; i >= 0; i-- {
+		out = append(out, sessions[i])
+	}
+
+	return out
+}
+
+// GetCurrentSession returns the session in progress, or the most recent one
+// when the player is not playing. An empty playerId uses the daily recap
+// player.
+func (a *App) GetCurrentSession(playerId string) (PlaySession, error) {
+	sessions := a.playSessions(playerId)
+	if len(sessions) == 0 {
+		return PlaySession{}, fmt.Errorf("no games found")
+	}
+
+	return sessions[len(sessions)-1], nil
+}
+
+// scheduleSessionUpdate emits "session-updated" with the current session once
+// new replays stop arriving for sessionUpdateDelay.
+func (a *App) scheduleSessionUpdate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.sessionTimer != nil {
+		a.sessionTimer.Stop()
+	}
+	a.sessionTimer = time.AfterFunc(sessionUpdateDelay, func() {
+		session, err := a.GetCurrentSession("")
+		if err != nil {
+			log.Printf("Error building current session: %v", err)
+			return
+		}
+		runtime.EventsEmit(a.ctx, "session-updated", session)
+	})
+}
@@ -3,16 +3,38 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 )
 
 type Settings struct {
+	SchemaVersion     int      `json:"schemaVersion"`
+	Folders           []string `json:"folders,omitempty"`
 	PlayerIds         []string `json:"playerIds,omitempty"`
 	FavoritePlayerIds []string `json:"favoritePlayerIds,omitempty"`
 	DateRangeFrom     string   `json:"dateRangeFrom,omitempty"`
 	DateRangeTo       string   `json:"dateRangeTo,omitempty"`
 	DailyRecapUser    string   `json:"dailyRecapUser,omitempty"`
+	// DayStartsAt ("HH:MM") and TimeZone (IANA name) define the day used by
+	// the daily recap. They default to midnight in the local time zone.
+	DayStartsAt string `json:"dayStartsAt,omitempty"`
+	TimeZone    string `json:"timeZone,omitempty"`
+
+	// ReplayFolders are scanned in addition to the Steam save folders, in
+	// every profile.
+	ReplayFolders []ReplayFolder `json:"replayFolders,omitempty"`
+
+	Profiles      []SettingsProfile `json:"profiles,omitempty"`
+	ActiveProfile string            `json:"activeProfile,omitempty"`
+
+	// UpdateChannel is "stable" (the default) or "prerelease".
+	UpdateChannel string `json:"updateChannel,omitempty"`
+
+	// TelemetryDisabled stops the init event sent on startup.
+	TelemetryDisabled bool `json:"telemetryDisabled,omitempty"`
 }
 
 type PlayerIdsOption struct {
@@ -31,87 +53,100 @@ func getSettingsFilePath() (string, error) {
 	return settingsFilePath, nil
 }
 
+// GetSettings reads the settings file, migrating it from older schema
+// versions. A missing file yields the defaults; an unreadable one is an error.
 func (a *App) GetSettings() (Settings, error) {
 	settingsFilePath, err := getSettingsFilePath()
 	if err != nil {
 		return Settings{}, fmt.Errorf("failed to get settings file path: %w", err)
 	}
 
-	f, err := os.Open(settingsFilePath)
+	data, err := os.ReadFile(settingsFilePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return Settings{}, nil
+			return Settings{SchemaVersion: settingsSchemaVersion}, nil
 		}
-		return Settings{}, fmt.Errorf("failed to open settings file: %w", err)
+		return Settings{}, fmt.Errorf("failed to read settings file: %w", err)
 	}
-	defer f.Close()
 
-	var settings Settings
-	if err := json.NewDecoder(f).Decode(&settings); err != nil {
-		fmt.Printf("failed to decode settings, returning default settings: %v\n", err)
-		return Settings{}, nil
+	settings, err := decodeSettings(data)
+	if err != nil {
+		return Settings{}, fmt.Errorf("failed to decode settings: %w", err)
 	}
 
 	return settings, nil
 }
 
-func (a *App) SaveSettings(settings Settings) {
+// SaveSettings validates settings and writes them atomically. Invalid settings,
+// including player IDs the app has never seen, are rejected and leave the file
+// untouched.
+func (a *App) SaveSettings(settings Settings) error {
+	settings.PlayerIds = normalizePlayerIds(settings.PlayerIds)
+	settings.FavoritePlayerIds = normalizePlayerIds(settings.FavoritePlayerIds)
+	settings.DailyRecapUser = strings.TrimSpace(settings.DailyRecapUser)
+	settings.SchemaVersion = settingsSchemaVersion
+	settings.storeActiveProfile()
+
+	if err := settings.validate(); err != nil {
+		return err
+	}
+	previous, err := a.GetSettings()
+	if err != nil {
+		log.Printf("Error reading current settings, checking every player ID: %v", err)
+	}
+	if err := settings.validateNewPlayerIds(previous); err != nil {
+		return err
+	}
+
 	settingsFilePath, err := getSettingsFilePath()
 	if err != nil {
-		fmt.Printf("failed to get settings file path: %v\n", err)
+		return fmt.Errorf("failed to get settings file path: %w", err)
 	}
 
 	settingsJSON, err := json.Marshal(settings)
 	if err != nil {
-		fmt.Printf("failed to marshal settings: %v", err)
+		return fmt.Errorf("failed to marshal settings: %w", err)
 	}
 
-	err = os.WriteFile(settingsFilePath, settingsJSON, 0644)
-	if err != nil {
-		fmt.Printf("failed to write settings to file: %v", err)
+	if err := writeFileAtomic(settingsFilePath, settingsJSON, 0644); err != nil {
+		return fmt.Errorf("failed to write settings: %w", err)
 	}
+
+	return nil
 }
 
+// GetPlayerIdsOptions lists the local accounts found in the active profile's
+// folders, or in the Steam and custom folders when the profile has none. Only
+// replays not yet in the identity index are parsed. Labels list the names of
+// an account from the most recently used.
 func (a *App) GetPlayerIdsOptions() []PlayerIdsOption {
-	saveFolders, err := findWarnoSaveFolders()
-	if err != nil {
-		fmt.Printf("failed to find Warno save folders: %v\n", err)
-		return nil
+	folders := a.activeProfileFolders()
+	if len(folders) == 0 {
+		for _, value := range a.replayFolderSources() {
+			folders = append(folders, value)
+		}
 	}
-
-	var folderKeys []string
-	for _, value := range saveFolders {
-		folderKeys = append(folderKeys, value)
+	for i, folder := range folders {
+		if abs, err := filepath.Abs(folder); err == nil {
+			folders[i] = abs
+		}
 	}
 
-	replays := getReplays(folderKeys)
+	indexReplayFolders(folders)
 
-	var options []PlayerIdsOption
-	playerMap := make(map[string]string)
-	for _, replay := range replays {
-		var player = replay.Warno.Players[replay.Warno.LocalPlayerKey]
-
-		if existingNames, exists := playerMap[player.PlayerUserId]; exists {
-			nameSet := make(map[string]struct{})
-			for _, name := range append(strings.Split(existingNames, ", "), player.PlayerName) {
-				nameSet[name] = struct{}{}
-			}
-
-			var uniqueNames []string
-			for name := range nameSet {
-				uniqueNames = append(uniqueNames, name)
-			}
+	options := []PlayerIdsOption{}
+	for _, identity := range localIdentities.localIdentitiesIn(folders) {
+		sort.SliceStable(identity.Names, func(i, j int) bool {
+			return identity.Names[i].LastSeen.After(identity.Names[j].LastSeen)
+		})
 
-			playerMap[player.PlayerUserId] = strings.Join(uniqueNames, ", ")
-		} else {
-			playerMap[player.PlayerUserId] = player.PlayerName
+		names := make([]string, 0, len(identity.Names))
+		for _, n := range identity.Names {
+			names = append(names, n.Name)
 		}
-	}
-
-	for userId, names := range playerMap {
 		options = append(options, PlayerIdsOption{
-			Label: names,
-			Value: userId,
+			Label: strings.Join(names, ", "),
+			Value: identity.EugenId,
 		})
 	}
 
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+const defaultProfileName = "Default"
+
+// SettingsProfile is a named set of replay folders, accounts, filters and
+// favorites. The fields of the active profile are mirrored at the top level
+// of Settings, which is what the rest of the app reads and edits.
+type SettingsProfile struct {
+	Name              string   `json:"name"`
+	Folders           []string `json:"folders,omitempty"`
+	PlayerIds         []string `json:"playerIds,omitempty"`
+	FavoritePlayerIds []string `json:"favoritePlayerIds,omitempty"`
+	DateRangeFrom     string   `json:"dateRangeFrom,omitempty"`
+	DateRangeTo       string   `json:"dateRangeTo,omitempty"`
+	DailyRecapUser    string   `json:"dailyRecapUser,omitempty"`
+}
+
+// migrateSettingsV1 moves the single set of filters into a default profile.
+func migrateSettingsV1(doc map[string]any) error {
+	profile := map[string]any{"name": defaultProfileName}
+	for _, key := range []string{"playerIds", "favoritePlayerIds", "dateRangeFrom", "dateRangeTo", "dailyRecapUser"} {
+		if v, ok := doc[key]; ok {
+			profile[key] = v
+		}
+	}
+
+	doc["profiles"] = []any{profile}
+	doc["activeProfile"] = defaultProfileName
+
+	return nil
+}
+
+func (s *Settings) profileIndex(name string) int {
+	for i, p := range s.Profiles {
+		if strings.EqualFold(p.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyActiveProfile copies the active profile to the top-level fields. An
+// unknown active profile falls back to the first one.
+func (s *Settings) applyActiveProfile() {
+	if len(s.Profiles) == 0 {
+		return
+	}
+
+	i := s.profileIndex(s.ActiveProfile)
+	if i < 0 {
+		log.Printf("Active profile %q not found, using %q", s.ActiveProfile, s.Profiles[0].Name)
+		i = 0
+	}
+
+	p := s.Profiles[i]
+	s.ActiveProfile = p.Name
+	s.Folders = p.Folders
+	s.PlayerIds = p.PlayerIds
+	s.FavoritePlayerIds = p.FavoritePlayerIds
+	s.DateRangeFrom = p.DateRangeFrom
+	s.DateRangeTo = p.DateRangeTo
+	s.DailyRecapUser = p.DailyRecapUser
+}
+
+// storeActiveProfile copies the top-level fields into the active profile,
+// creating a default profile when there is none.
+func (s *Settings) storeActiveProfile() {
+	if s.ActiveProfile == "" {
+		s.ActiveProfile = defaultProfileName
+	}
+
+	i := s.profileIndex(s.ActiveProfile)
+	if i < 0 {
+		s.Profiles = append(s.Profiles, SettingsProfile{Name: s.ActiveProfile})
+		i = len(s.Profiles) - 1
+	}
+
+	s.Profiles[i] = SettingsProfile{
+		Name:              s.Profiles[i].Name,
+		Folders:           s.Folders,
+		PlayerIds:         s.PlayerIds,
+		FavoritePlayerIds: s.FavoritePlayerIds,
+		DateRangeFrom:     s.DateRangeFrom,
+		DateRangeTo:       s.DateRangeTo,
+		DailyRecapUser:    s.DailyRecapUser,
+	}
+	s.ActiveProfile = s.Profiles[i].Name
+}
+
+func validateProfileName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("profile name is required")
+	}
+	if name != strings.TrimSpace(name) {
+		return fmt.Errorf("profile name %q has leading or trailing spaces", name)
+	}
+	return nil
+}
+
+// updateProfiles loads the settings, lets change edit them and saves the
+// result. The top-level fields are stored into the active profile first.
+func (a *App) updateProfiles(change func(s *Settings) error) (Settings, error) {
+	settings, err := a.GetSettings()
+	if err != nil {
+		return Settings{}, err
+	}
+	settings.storeActiveProfile()
+
+	if err := change(&settings); err != nil {
+		return Settings{}, err
+	}
+	settings.applyActiveProfile()
+
+	if err := a.SaveSettings(settings); err != nil {
+		return Settings{}, err
+	}
+
+	return a.GetSettings()
+}
+
+// GetSettingsProfiles returns all profiles; the active one is named by the
+// ActiveProfile setting.
+func (a *App) GetSettingsProfiles() ([]SettingsProfile, error) {
+	settings, err := a.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	if len(settings.Profiles) == 0 {
+		settings.storeActiveProfile()
+	}
+
+	return settings.Profiles, nil
+}
+
+// CreateSettingsProfile adds an empty profile without switching to it.
+func (a *App) CreateSettingsProfile(name string) (Settings, error) {
+	return a.updateProfiles(func(s *Settings) error {
+		if err := validateProfileName(name); err != nil {
+			return err
+		}
+		if s.profileIndex(name) >= 0 {
+			return fmt.Errorf("profile %q already exists", name)
+		}
+		s.Profiles = append(s.Profiles, SettingsProfile{Name: name})
+		return nil
+	})
+}
+
+// CloneSettingsProfile copies source into a new profile and switches to it.
+func (a *App) CloneSettingsProfile(source, name string) (Settings, error) {
+	return a.updateProfiles(func(s *Settings) error {
+		if err := validateProfileName(name); err != nil {
+			return err
+		}
+		if s.profileIndex(name) >= 0 {
+			return fmt.Errorf("profile %q already exists", name)
+		}
+		i := s.profileIndex(source)
+		if i < 0 {
+			return fmt.Errorf("profile %q not found", source)
+		}
+
+		clone := s.Profiles[i]
+		clone.Name = name
+		clone.Folders = append([]string(nil), clone.Folders...)
+		clone.PlayerIds = append([]string(nil), clone.PlayerIds...)
+		clone.FavoritePlayerIds = append([]string(nil), clone.FavoritePlayerIds...)
+
+		s.Profiles = append(s.Profiles, clone)
+		s.ActiveProfile = name
+		return nil
+	})
+}
+
+func (a *App) SwitchSettingsProfile(name string) (Settings, error) {
+	return a.updateProfiles(func(s *Settings) error {
+		if s.profileIndex(name) < 0 {
+			return fmt.Errorf("profile %q not found", name)
+		}
+		s.ActiveProfile = name
+		return nil
+	})
+}
+
+// DeleteSettingsProfile removes a profile. The last profile cannot be
+// deleted; deleting the active one switches to the first remaining profile.
+func (a *App) DeleteSettingsProfile(name string) (Settings, error) {
+	return a.updateProfiles(func(s *Settings) error {
+		i := s.profileIndex(name)
+		if i < 0 {
+			return fmt.Errorf("profile %q not found", name)
+		}
+		if len(s.Profiles) == 1 {
+			return errors.New("cannot delete the only profile")
+		}
+
+		s.Profiles = append(s.Profiles[:i], s.Profiles[i+1:]...)
+		if strings.EqualFold(s.ActiveProfile, name) {
+			s.ActiveProfile = s.Profiles[0].Name
+		}
+		return nil
+	})
+}
+
+// activeProfileFolders returns the replay folders of the active profile, or
+// nil when it has none.
+func (a *App) activeProfileFolders() []string {
+	settings, err := a.GetSettings()
+	if err != nil {
+		log.Printf("Error loading settings: %v", err)
+		return nil
+	}
+	return settings.Folders
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// settingsSchemaVersion is the version written by SaveSettings. Files without
+// a version predate versioning and are treated as version 0.
+const settingsSchemaVersion = 3
+
+// settingsMigrations[v] upgrades a raw settings document from version v to
+// v+1.
+var settingsMigrations = []func(doc map[string]any) error{
+	migrateSettingsV0,
+	migrateSettingsV1,
+	migrateSettingsV2,
+}
+
+// migrateSettingsV0 cleans up values written before validation existed: IDs
+// with stray whitespace or duplicates, and plain dates in the date range.
+func migrateSettingsV0(doc map[string]any) error {
+	for _, key := range []string{"playerIds", "favoritePlayerIds"} {
+		raw, ok := doc[key].([]any)
+		if !ok {
+			continue
+		}
+		var ids []string
+		for _, v := range raw {
+			if s, ok := v.(string); ok {
+				ids = append(ids, s)
+			}
+		}
+		doc[key] = normalizePlayerIds(ids)
+	}
+
+	for _, key := range []string{"dateRangeFrom", "dateRangeTo"} {
+		s, ok := doc[key].(string)
+		if !ok || s == "" {
+			continue
+		}
+		t, err := parseFilterTime(s)
+		if err != nil {
+			log.Printf("Dropping unreadable %s %q from settings: %v", key, s, err)
+			delete(doc, key)
+			continue
+		}
+		doc[key] = t.UTC().Format(time.RFC3339)
+	}
+
+	return nil
+}
+
+// cleanPlayerIds drops the IDs SaveSettings would reject from doc[key].
+func cleanPlayerIds(doc map[string]any, key string) {
+	raw, ok := doc[key].([]any)
+	if !ok {
+		delete(doc, key)
+		return
+	}
+	var ids []string
+	for _, v := range raw {
+		s, _ := v.(string)
+		if s = strings.TrimSpace(s); !validPlayerId(s) {
+			log.Printf("Dropping invalid %s %q from settings", key, s)
+			continue
+		}
+		ids = append(ids, s)
+	}
+	doc[key] = normalizePlayerIds(ids)
+}
+
+// cleanFilterFields drops the filter values SaveSettings would reject. The
+// same fields are stored at the top level and in every profile.
+func cleanFilterFields(doc map[string]any) {
+	for _, key := range []string{"playerIds", "favoritePlayerIds"} {
+		if _, ok := doc[key]; ok {
+			cleanPlayerIds(doc, key)
+		}
+	}
+
+	if v, ok := doc["dailyRecapUser"]; ok {
+		s, _ := v.(string)
+		if s = strings.TrimSpace(s); validPlayerId(s) {
+			doc["dailyRecapUser"] = s
+		} else {
+			log.Printf("Dropping invalid dailyRecapUser %q from settings", s)
+			delete(doc, "dailyRecapUser")
+		}
+	}
+
+	if raw, ok := doc["folders"].([]any); ok {
+		var folders []string
+		for _, v := range raw {
+			s, _ := v.(string)
+			if strings.TrimSpace(s) != "" && !containsString(folders, s) {
+				folders = append(folders, s)
+			}
+		}
+		doc["folders"] = folders
+	}
+}
+
+// migrateSettingsV2 drops values older versions migrated or saved without
+// validating them, which would otherwise make every SaveSettings fail:
+// invalid player IDs, empty or repeated folders and unreadable time settings.
+func migrateSettingsV2(doc map[string]any) error {
+	cleanFilterFields(doc)
+	if profiles, ok := doc["profiles"].([]any); ok {
+		for _, p := range profiles {
+			if profile, ok := p.(map[string]any); ok {
+				cleanFilterFields(profile)
+			}
+		}
+	}
+
+	if raw, ok := doc["replayFolders"].([]any); ok {
+		var folders []any
+		var paths []string
+		for _, v := range raw {
+			folder, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			path, _ := folder["path"].(string)
+			if strings.TrimSpace(path) == "" {
+				continue
+			}
+			duplicate := false
+			for _, p := range paths {
+				if sameFolder(p, path) {
+					duplicate = true
+					break
+				}
+			}
+			if duplicate {
+				log.Printf("Dropping repeated replay folder %s from settings", path)
+				continue
+			}
+			paths = append(paths, path)
+			folders = append(folders, folder)
+		}
+		doc["replayFolders"] = folders
+	}
+
+	if s, ok := doc["dayStartsAt"].(string); ok && s != "" {
+		if _, err := time.Parse("15:04", s); err != nil {
+			log.Printf("Dropping unreadable dayStartsAt %q from settings", s)
+			delete(doc, "dayStartsAt")
+		}
+	}
+	if s, ok := doc["timeZone"].(string); ok && s != "" {
+		if _, err := time.LoadLocation(s); err != nil {
+			log.Printf("Dropping unknown timeZone %q from settings", s)
+			delete(doc, "timeZone")
+		}
+	}
+
+	return nil
+}
+
+func decodeSettings(data []byte) (Settings, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Settings{}, err
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+
+	version := 0
+	if v, ok := doc["schemaVersion"].(float64); ok {
+		version = int(v)
+	}
+	if version < 0 {
+		return Settings{}, fmt.Errorf("invalid schema version %d", version)
+	}
+
+	if version > settingsSchemaVersion {
+		log.Printf("Settings were written by a newer version (schema %d, supported %d), unknown fields are ignored", version, settingsSchemaVersion)
+	}
+	for ; version < settingsSchemaVersion; version++ {
+		if err := settingsMigrations[version](doc); err != nil {
+			return Settings{}, fmt.Errorf("migrating settings from schema %d: %w", version, err)
+		}
+		doc["schemaVersion"] = version + 1
+	}
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	var settings Settings
+	if err := json.Unmarshal(migrated, &settings); err != nil {
+		return Settings{}, err
+	}
+	settings.applyActiveProfile()
+
+	return settings, nil
+}
+
+// normalizePlayerIds trims IDs and drops empty and repeated ones.
+func normalizePlayerIds(ids []string) []string {
+	var out []string
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id != "" && !containsString(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func validPlayerId(id string) bool {
+	n, err := strconv.ParseUint(id, 10, 64)
+	return err == nil && n > 0
+}
+
+// knownPlayerId reports whether the app has seen id: in the local replays, in
+// the leaderboard names or in the notes.
+func knownPlayerId(id string) bool {
+	if _, ok := localIdentities.resolve([]string{id})[id]; ok {
+		return true
+	}
+	if n, err := strconv.Atoi(id); err == nil && playerNames.known(n) {
+		return true
+	}
+	if filePath, err := getNotesFilePath(id); err == nil {
+		if _, err := os.Stat(filePath); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// storedPlayerIds returns every player ID the settings hold, in any profile.
+func (s Settings) storedPlayerIds() map[string]struct{} {
+	ids := make(map[string]struct{})
+	add := func(values ...string) {
+		for _, id := range values {
+			ids[id] = struct{}{}
+		}
+	}
+
+	add(s.PlayerIds...)
+	add(s.FavoritePlayerIds...)
+	add(s.DailyRecapUser)
+	for _, p := range s.Profiles {
+		add(p.PlayerIds...)
+		add(p.FavoritePlayerIds...)
+		add(p.DailyRecapUser)
+	}
+	return ids
+}
+
+// validateNewPlayerIds checks the player IDs that are not in previous yet.
+// Tracked accounts have to be local accounts; favorites and the recap player
+// only have to be players the app has seen. IDs stored before are not checked
+// again, so a player that left the replay folders does not block saving.
+func (s Settings) validateNewPlayerIds(previous Settings) error {
+	stored := previous.storedPlayerIds()
+	isNew := func(id string) bool {
+		_, ok := stored[id]
+		return id != "" && !ok
+	}
+
+	var errs []error
+	for _, id := range s.PlayerIds {
+		if !isNew(id) {
+			continue
+		}
+		if identity, ok := localIdentities.resolve([]string{id})[id]; !ok || !identity.IsLocal {
+			errs = append(errs, fmt.Errorf("playerIds: %s is not an account found in the replay folders", id))
+		}
+	}
+	for _, id := range s.FavoritePlayerIds {
+		if isNew(id) && !knownPlayerId(id) {
+			errs = append(errs, fmt.Errorf("favoritePlayerIds: unknown player %s", id))
+		}
+	}
+	if isNew(s.DailyRecapUser) && !knownPlayerId(s.DailyRecapUser) {
+		errs = append(errs, fmt.Errorf("dailyRecapUser: unknown player %s", s.DailyRecapUser))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid settings: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// validate reports every invalid field at once.
+func (s Settings) validate() error {
+	var errs []error
+
+	for _, field := range []struct {
+		name string
+		ids  []string
+	}{{"playerIds", s.PlayerIds}, {"favoritePlayerIds", s.FavoritePlayerIds}} {
+		for _, id := range field.ids {
+			if !validPlayerId(id) {
+				errs = append(errs, fmt.Errorf("%s: %q is not an Eugen player ID", field.name, id))
+			}
+		}
+	}
+	if s.DailyRecapUser != "" && !validPlayerId(s.DailyRecapUser) {
+		errs = append(errs, fmt.Errorf("dailyRecapUser: %q is not an Eugen player ID", s.DailyRecapUser))
+	}
+
+	var from, to time.Time
+	var err error
+	if s.DateRangeFrom != "" {
+		if from, err = time.Parse(time.RFC3339, s.DateRangeFrom); err != nil {
+			errs = append(errs, fmt.Errorf("dateRangeFrom: %q is not an RFC 3339 date", s.DateRangeFrom))
+		}
+	}
+	if s.DateRangeTo != "" {
+		if to, err = time.Parse(time.RFC3339, s.DateRangeTo); err != nil {
+			errs = append(errs, fmt.Errorf("dateRangeTo: %q is not an RFC 3339 date", s.DateRangeTo))
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		errs = append(errs, errors.New("dateRangeFrom is after dateRangeTo"))
+	}
+
+	if s.DayStartsAt != "" {
+		if _, err := time.Parse("15:04", s.DayStartsAt); err != nil {
+			errs = append(errs, fmt.Errorf("dayStartsAt: %q is not a HH:MM time", s.DayStartsAt))
+		}
+	}
+	if s.TimeZone != "" {
+		if _, err := time.LoadLocation(s.TimeZone); err != nil {
+			errs = append(errs, fmt.Errorf("timeZone: unknown time zone %q", s.TimeZone))
+		}
+	}
+
+	switch s.UpdateChannel {
+	case "", updateChannelStable, updateChannelPrerelease:
+	default:
+		errs = append(errs, fmt.Errorf("updateChannel: unknown channel %q", s.UpdateChannel))
+	}
+
+	for i, f := range s.ReplayFolders {
+		if strings.TrimSpace(f.Path) == "" {
+			errs = append(errs, errors.New("replayFolders: folder path is required"))
+			continue
+		}
+		if s.replayFolderIndex(f.Path) != i {
+			errs = append(errs, fmt.Errorf("replayFolders: %s is listed twice", f.Path))
+		}
+	}
+
+	names := make(map[string]struct{}, len(s.Profiles))
+	for _, p := range s.Profiles {
+		if err := validateProfileName(p.Name); err != nil {
+			errs = append(errs, fmt.Errorf("profiles: %w", err))
+			continue
+		}
+		if _, dup := names[strings.ToLower(p.Name)]; dup {
+			errs = append(errs, fmt.Errorf("profiles: %q is used twice", p.Name))
+		}
+		names[strings.ToLower(p.Name)] = struct{}{}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid settings: %w", errors.Join(errs...))
+	}
+	return nil
+}
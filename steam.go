@@ -2,10 +2,7 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -38,6 +35,8 @@ type SteamPlayer struct {
 	GameExtraInfo            string `json:"gameextrainfo,omitempty"`
 	GameID                   string `json:"gameid,omitempty"`
 	LocCountryCode           string `json:"loccountrycode,omitempty"`
+	// LocalAvatar is the asset server path of the downloaded AvatarMedium.
+	LocalAvatar string `json:"localAvatar,omitempty"`
 }
 
 func getSteamPath() (string, error) {
@@ -78,32 +77,17 @@ func getSteamUsername(steamID, userdataPath string) (string, error) {
 	return "Unknown", nil
 }
 
+// GetSteamPlayer returns the Steam profile of steamID from the cache or the
+// Steam API. It returns nil without an error when the profile is unknown and
+// no Steam API key is configured.
 func (a *App) GetSteamPlayer(steamID string) (*SteamPlayer, error) {
-	url := fmt.Sprintf("https://api.steampowered.com/ISteamUser/GetPlayerSummaries/v0002/?key=%s&steamids=%s", steamApiKey, steamID)
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP error: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var summaries SteamPlayersResponse
-	if err := json.Unmarshal(body, &summaries); err != nil {
-		return nil, err
-	}
-
-	if len(summaries.Response.Players) == 0 {
+	player, ok := steamPlayers([]string{steamID})[steamID]
+	if !ok {
+		if steamApiKey == "" {
+			return nil, nil
+		}
 		return nil, fmt.Errorf("no player data found")
 	}
 
-	return &summaries.Response.Players[0], nil
+	return &player, nil
 }
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	steamProfileCacheTTL = 24 * time.Hour
+	// steamProfileMissingTTL is how long a SteamID that Steam did not return,
+	// like a private or deleted profile, is left alone.
+	steamProfileMissingTTL = 6 * time.Hour
+	// steamLookupBatchSize is the most SteamIDs GetPlayerSummaries accepts.
+	steamLookupBatchSize = 100
+	// avatarRoute is the asset server path local avatars are served from.
+	avatarRoute = "/avatars/"
+)
+
+var steamClient = &http.Client{Timeout: 15 * time.Second}
+
+// cachedSteamProfile is a profile Steam returned, or only MissingAt when Steam
+// did not return the SteamID. A profile fetched before is kept in that case.
+type cachedSteamProfile struct {
+	Player    SteamPlayer `json:"player"`
+	FetchedAt time.Time   `json:"fetchedAt"`
+	MissingAt time.Time   `json:"missingAt"`
+}
+
+func (e cachedSteamProfile) found() bool {
+	return e.Player.SteamID != ""
+}
+
+func (e cachedSteamProfile) fresh() bool {
+	return (e.found() && time.Since(e.FetchedAt) <= steamProfileCacheTTL) ||
+		time.Since(e.MissingAt) <= steamProfileMissingTTL
+}
+
+// steamProfileCache keeps the Steam profiles fetched so far. Like the player
+// name cache, expired entries are still served when Steam cannot be reached.
+type steamProfileCache struct {
+	mu      sync.Mutex
+	loaded  bool
+	entries map[string]cachedSteamProfile
+}
+
+var steamProfiles = &steamProfileCache{}
+
+func getSteamProfileCacheFilePath() (string, error) {
+	dir, err := getLocalAppDataDir("warno-replays-analyser")
+	if err != nil {
+		return "", fmt.Errorf("failed to get app data directory: %w", err)
+	}
+	return filepath.Join(dir, "steamProfiles.json"), nil
+}
+
+func (c *steamProfileCache) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	c.entries = make(map[string]cachedSteamProfile)
+
+	filePath, err := getSteamProfileCacheFilePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		log.Printf("Error reading Steam profile cache, starting empty: %v", err)
+		c.entries = make(map[string]cachedSteamProfile)
+	}
+}
+
+func (c *steamProfileCache) save() error {
+	filePath, err := getSteamProfileCacheFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("marshaling Steam profile cache: %w", err)
+	}
+	return writeFileAtomic(filePath, data, 0644)
+}
+
+// lookup splits ids into cached profiles and IDs whose entry is missing or
+// expired. IDs Steam recently did not return are not stale.
+func (c *steamProfileCache) lookup(ids []string) (map[string]SteamPlayer, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	players := make(map[string]SteamPlayer, len(ids))
+	var stale []string
+	for _, id := range ids {
+		entry, ok := c.entries[id]
+		if ok && entry.found() {
+			players[id] = entry.Player
+		}
+		if !ok || !entry.fresh() {
+			stale = append(stale, id)
+		}
+	}
+
+	return players, stale
+}
+
+// store records the fetched profiles and marks the requested IDs Steam did
+// not return as missing.
+func (c *steamProfileCache) store(requested []string, players []SteamPlayer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	now := time.Now().UTC()
+	returned := make(map[string]struct{}, len(players))
+	for _, p := range players {
+		c.entries[p.SteamID] = cachedSteamProfile{Player: p, FetchedAt: now}
+		returned[p.SteamID] = struct{}{}
+	}
+	for _, id := range requested {
+		if _, ok := returned[id]; !ok {
+			entry := c.entries[id]
+			entry.MissingAt = now
+			c.entries[id] = entry
+		}
+	}
+
+	return c.save()
+}
+
+// fetchSteamPlayers requests the profiles of ids in batches. Profiles of
+// batches that succeeded are returned along with the IDs those batches asked
+// for and the first error.
+func fetchSteamPlayers(ids []string) ([]SteamPlayer, []string, error) {
+	var players []SteamPlayer
+	var requested []string
+	var firstErr error
+
+	for start := 0; start < len(ids); start += steamLookupBatchSize {
+		end := min(start+steamLookupBatchSize, len(ids))
+
+		batch, err := fetchSteamPlayerBatch(ids[start:end])
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		players = append(players, batch...)
+		requested = append(requested, ids[start:end]...)
+	}
+
+	return players, requested, firstErr
+}
+
+func fetchSteamPlayerBatch(ids []string) ([]SteamPlayer, error) {
+	query := url.Values{}
+	query.Set("key", steamApiKey)
+	query.Set("steamids", strings.Join(ids, ","))
+
+	resp, err := steamClient.Get("https://api.steampowered.com/ISteamUser/GetPlayerSummaries/v0002/?" + query.Encode())
+	if err != nil {
+		// The URL holds the API key; keep it out of the logs.
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("requesting Steam profiles: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP error: %d", resp.StatusCode)
+	}
+
+	var summaries SteamPlayersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&summaries); err != nil {
+		return nil, fmt.Errorf("decoding Steam profiles: %w", err)
+	}
+
+	return summaries.Response.Players, nil
+}
+
+func getAvatarDir() (string, error) {
+	return getLocalAppDataDir("warno-replays-analyser", "avatars")
+}
+
+// avatarFileName names the stored avatar after its hash, so a changed avatar
+// is downloaded again.
+func avatarFileName(p SteamPlayer) string {
+	hash := p.AvatarHash
+	if hash == "" {
+		hash = "avatar"
+	}
+	return sanitizeFileName(p.SteamID + "_" + hash + path.Ext(p.AvatarMedium))
+}
+
+// storeAvatar downloads the medium avatar of p unless it is already stored,
+// and points p.LocalAvatar at the stored copy.
+func storeAvatar(p *SteamPlayer) error {
+	if p.AvatarMedium == "" {
+		return nil
+	}
+
+	dir, err := getAvatarDir()
+	if err != nil {
+		return err
+	}
+	name := avatarFileName(*p)
+	filePath := filepath.Join(dir, name)
+
+	if _, err := os.Stat(filePath); err != nil {
+		resp, err := steamClient.Get(p.AvatarMedium)
+		if err != nil {
+			return fmt.Errorf("downloading avatar of %s: %w", p.SteamID, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("downloading avatar of %s: HTTP error: %d", p.SteamID, resp.StatusCode)
+		}
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("downloading avatar of %s: %w", p.SteamID, err)
+		}
+		if err := writeFileAtomic(filePath, data, 0644); err != nil {
+			return err
+		}
+	}
+
+	p.LocalAvatar = avatarRoute + name
+	return nil
+}
+
+// steamPlayers returns the profiles of ids, fetching the missing and expired
+// ones. Without a Steam API key only cached profiles are returned.
+func steamPlayers(ids []string) map[string]SteamPlayer {
+	ids = normalizePlayerIds(ids)
+	players, stale := steamProfiles.lookup(ids)
+	if len(stale) == 0 || steamApiKey == "" {
+		return players
+	}
+
+	fetched, requested, err := fetchSteamPlayers(stale)
+	if err != nil {
+		log.Printf("Error fetching Steam profiles (%d of %d resolved): %v", len(fetched), len(stale), err)
+	}
+	if len(requested) == 0 {
+		return players
+	}
+
+	for i := range fetched {
+		if err := storeAvatar(&fetched[i]); err != nil {
+			log.Printf("Error storing avatar: %v", err)
+		}
+		players[fetched[i].SteamID] = fetched[i]
+	}
+	if err := steamProfiles.store(requested, fetched); err != nil {
+		log.Printf("Error saving Steam profile cache: %v", err)
+	}
+
+	return players
+}
+
+// GetSteamPlayers returns the Steam profiles of steamIds keyed by SteamID, so
+// a whole replay list can be resolved at once. Unknown IDs are left out.
+func (a *App) GetSteamPlayers(steamIds []string) map[string]SteamPlayer {
+	return steamPlayers(steamIds)
+}
+
+// prefetchSteamProfiles refreshes the profiles of every player in the
+// identity index that has a SteamID.
+func (a *App) prefetchSteamProfiles() {
+	if steamApiKey == "" {
+		return
+	}
+
+	a.refreshIdentities()
+
+	steamPlayers(localIdentities.steamIds())
+}
+
+// avatarHandler serves the stored avatars to the frontend; every other path is
+// left to the embedded assets.
+type avatarHandler struct{}
+
+func (avatarHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, avatarRoute) {
+		http.NotFound(w, r)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, avatarRoute)
+	if name == "" || name != filepath.Base(name) {
+		http.NotFound(w, r)
+		return
+	}
+
+	dir, err := getAvatarDir()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "max-age=86400")
+	http.ServeFile(w, r, filepath.Join(dir, name))
+}
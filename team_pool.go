@@ -0,0 +1,584 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	replayBundleFormatVersion = 1
+	replayBundleManifestName  = "bundle.json"
+	replayBundleReplaysDir    = "replays/"
+	replayBundleExtension     = ".wrabundle"
+)
+
+// ReplayBundle is the manifest of an exported bundle. The bundle itself is a
+// zip archive holding the manifest and, optionally, the original .rpl3 files
+// under replays/.
+type ReplayBundle struct {
+	FormatVersion int                 `json:"formatVersion"`
+	AppVersion    string              `json:"appVersion"`
+	Member        string              `json:"member"`
+	ExportedAt    time.Time           `json:"exportedAt"`
+	Replays       []ReplayBundleEntry `json:"replays"`
+}
+
+type ReplayBundleEntry struct {
+	Data WarnoData `json:"data"`
+	File string    `json:"file,omitempty"`
+}
+
+type PoolContributor struct {
+	Member         string `json:"member"`
+	LocalPlayerKey string `json:"localPlayerKey"`
+}
+
+// PooledReplay is one game of the team pool. Member is the member who first
+// contributed it; when both players of a game are members, the second one is
+// only listed in Contributors.
+type PooledReplay struct {
+	SessionID    string            `json:"sessionId"`
+	Member       string            `json:"member"`
+	Contributors []PoolContributor `json:"contributors"`
+	ImportedAt   time.Time         `json:"importedAt"`
+	Data         WarnoData         `json:"data"`
+}
+
+type teamPool struct {
+	Replays []PooledReplay `json:"replays"`
+}
+
+type TeamPoolImportResult struct {
+	Member     string `json:"member"`
+	Imported   int    `json:"imported"`
+	Duplicates int    `json:"duplicates"`
+	Files      int    `json:"files"`
+}
+
+type PoolStats struct {
+	Games           int     `json:"games"`
+	Wins            int     `json:"wins"`
+	Losses          int     `json:"losses"`
+	Draws           int     `json:"draws"`
+	WinRate         float64 `json:"winRate"`
+	AverageDuration int     `json:"averageDuration"`
+	FirstGameAt     string  `json:"firstGameAt,omitempty"`
+	LastGameAt      string  `json:"lastGameAt,omitempty"`
+}
+
+type MemberPoolStats struct {
+	Member   string    `json:"member"`
+	EugenIds []string  `json:"eugenIds"`
+	Stats    PoolStats `json:"stats"`
+}
+
+// TeamPoolStats holds the pooled results per member and for the whole team.
+// Games between two members count for both of them but are left out of the
+// team's wins and losses and reported as InternalGames instead.
+type TeamPoolStats struct {
+	Team          PoolStats         `json:"team"`
+	InternalGames int               `json:"internalGames"`
+	Members       []MemberPoolStats `json:"members"`
+}
+
+var teamPoolMu sync.Mutex
+
+func getTeamPoolDir() (string, error) {
+	poolDir, err := getLocalAppDataDir("warno-replays-analyser", "teamPool")
+	if err != nil {
+		return "", fmt.Errorf("getting teamPool directory: %w", err)
+	}
+	return poolDir, nil
+}
+
+func loadTeamPool() (teamPool, error) {
+	var pool teamPool
+
+	poolDir, err := getTeamPoolDir()
+	if err != nil {
+		return pool, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(poolDir, "pool.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return pool, nil
+		}
+		return pool, fmt.Errorf("reading team pool: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &pool); err != nil {
+		return pool, fmt.Errorf("unmarshaling team pool: %w", err)
+	}
+
+	return pool, nil
+}
+
+func saveTeamPool(pool teamPool) error {
+	poolDir, err := getTeamPoolDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(pool)
+	if err != nil {
+		return fmt.Errorf("marshaling team pool: %w", err)
+	}
+
+	return writeFileAtomic(filepath.Join(poolDir, "pool.json"), data, 0644)
+}
+
+// replayOutcome maps the Victory code of a result to 1 for a win, -1 for a
+// defeat and 0 for a draw, from the local player's point of view.
+func replayOutcome(victory string) int {
+	switch victory {
+	case "4", "5", "6":
+		return 1
+	case "0", "1", "2":
+		return -1
+	default:
+		return 0
+	}
+}
+
+func writeReplayBundle(path string, bundle ReplayBundle, includeFiles bool) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating bundle: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	for i := range bundle.Replays {
+		entry := &bundle.Replays[i]
+		if !includeFiles || entry.Data.FilePath == "" {
+			continue
+		}
+
+		name := replayBundleReplaysDir + strconv.Itoa(i) + "_" + filepath.Base(entry.Data.FilePath)
+		if err := addFileToZip(zw, name, entry.Data.FilePath); err != nil {
+			log.Printf("Skipping replay file %s in bundle: %v", entry.Data.FilePath, err)
+			continue
+		}
+		entry.File = name
+	}
+
+	manifest, err := json.Marshal(bundle)
+	if err != nil {
+		return fmt.Errorf("marshaling bundle manifest: %w", err)
+	}
+
+	w, err := zw.Create(replayBundleManifestName)
+	if err != nil {
+		return fmt.Errorf("writing bundle manifest: %w", err)
+	}
+	if _, err := w.Write(manifest); err != nil {
+		return fmt.Errorf("writing bundle manifest: %w", err)
+	}
+
+	return zw.Close()
+}
+
+func addFileToZip(zw *zip.Writer, name, filePath string) error {
+	src, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, src)
+	return err
+}
+
+// importReplayBundle merges the bundle at path into the team pool. Original
+// replay files are extracted into the pool directory of the member.
+func importReplayBundle(path string) (TeamPoolImportResult, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return TeamPoolImportResult{}, fmt.Errorf("opening bundle: %w", err)
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	manifestFile, ok := files[replayBundleManifestName]
+	if !ok {
+		return TeamPoolImportResult{}, errors.New("bundle has no manifest")
+	}
+
+	var bundle ReplayBundle
+	if err := readZipJSON(manifestFile, &bundle); err != nil {
+		return TeamPoolImportResult{}, fmt.Errorf("reading bundle manifest: %w", err)
+	}
+	if bundle.FormatVersion > replayBundleFormatVersion {
+		return TeamPoolImportResult{}, fmt.Errorf("bundle format %d is newer than supported %d", bundle.FormatVersion, replayBundleFormatVersion)
+	}
+	if bundle.Member == "" {
+		return TeamPoolImportResult{}, errors.New("bundle has no member name")
+	}
+
+	poolDir, err := getTeamPoolDir()
+	if err != nil {
+		return TeamPoolImportResult{}, err
+	}
+	memberDir := filepath.Join(poolDir, "replays", sanitizeFileName(bundle.Member))
+
+	teamPoolMu.Lock()
+	defer teamPoolMu.Unlock()
+
+	pool, err := loadTeamPool()
+	if err != nil {
+		return TeamPoolImportResult{}, err
+	}
+
+	bySession := make(map[string]int, len(pool.Replays))
+	for i, r := range pool.Replays {
+		bySession[r.SessionID] = i
+	}
+
+	result := TeamPoolImportResult{Member: bundle.Member}
+	now := time.Now().UTC()
+
+	for _, entry := range bundle.Replays {
+		data := entry.Data
+		sessionID := data.Warno.Game.UniqueSessionId
+		if sessionID == "" {
+			continue
+		}
+
+		contributor := PoolContributor{Member: bundle.Member, LocalPlayerKey: data.Warno.LocalPlayerKey}
+
+		if i, exists := bySession[sessionID]; exists {
+			result.Duplicates++
+			if !hasContributor(pool.Replays[i].Contributors, bundle.Member) {
+				pool.Replays[i].Contributors = append(pool.Replays[i].Contributors, contributor)
+			}
+			continue
+		}
+
+		// The file name comes from the bundle, so it must not point elsewhere
+		// or replace a replay already extracted.
+		name := sanitizeFileName(filepath.Base(data.FileName))
+		if f, ok := files[entry.File]; ok && entry.File != "" && name != "_" {
+			if err := os.MkdirAll(memberDir, os.ModePerm); err != nil {
+				return result, fmt.Errorf("creating member directory: %w", err)
+			}
+			target := freePath(filepath.Join(memberDir, name))
+			if err := extractZipFile(f, target); err != nil {
+				log.Printf("Error extracting %s from bundle: %v", entry.File, err)
+			} else {
+				data.FilePath = target
+				result.Files++
+			}
+		}
+
+		bySession[sessionID] = len(pool.Replays)
+		pool.Replays = append(pool.Replays, PooledReplay{
+			SessionID:    sessionID,
+			Member:       bundle.Member,
+			Contributors: []PoolContributor{contributor},
+			ImportedAt:   now,
+			Data:         data,
+		})
+		result.Imported++
+	}
+
+	if err := saveTeamPool(pool); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func hasContributor(contributors []PoolContributor, member string) bool {
+	for _, c := range contributors {
+		if c.Member == member {
+			return true
+		}
+	}
+	return false
+}
+
+func readZipJSON(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return json.NewDecoder(rc).Decode(v)
+}
+
+func extractZipFile(f *zip.File, target string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	dst, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, rc); err != nil {
+		dst.Close()
+		os.Remove(target)
+		return err
+	}
+	return dst.Close()
+}
+
+type poolStatsAccumulator struct {
+	stats         PoolStats
+	totalDuration int
+	first, last   time.Time
+}
+
+func (acc *poolStatsAccumulator) add(data WarnoData, outcome int) {
+	acc.stats.Games++
+	switch {
+	case outcome > 0:
+		acc.stats.Wins++
+	case outcome < 0:
+		acc.stats.Losses++
+	default:
+		acc.stats.Draws++
+	}
+
+	if duration, err := strconv.Atoi(data.Warno.Result.Duration); err == nil {
+		acc.totalDuration += duration
+	}
+
+	if createdAt, err := time.Parse(time.RFC3339, data.CreatedAt); err == nil {
+		if acc.first.IsZero() || createdAt.Before(acc.first) {
+			acc.first = createdAt
+		}
+		if createdAt.After(acc.last) {
+			acc.last = createdAt
+		}
+	}
+}
+
+func (acc *poolStatsAccumulator) result() PoolStats {
+	s := acc.stats
+	if s.Wins+s.Losses > 0 {
+		s.WinRate = float64(s.Wins) / float64(s.Wins+s.Losses)
+	}
+	if s.Games > 0 {
+		s.AverageDuration = acc.totalDuration / s.Games
+	}
+	if !acc.first.IsZero() {
+		s.FirstGameAt = acc.first.Format(time.RFC3339)
+		s.LastGameAt = acc.last.Format(time.RFC3339)
+	}
+	return s
+}
+
+func buildTeamPoolStats(pool teamPool) TeamPoolStats {
+	var team poolStatsAccumulator
+	members := make(map[string]*poolStatsAccumulator)
+	memberIds := make(map[string]map[string]struct{})
+
+	var stats TeamPoolStats
+
+	for _, r := range pool.Replays {
+		if len(r.Contributors) > 1 {
+			stats.InternalGames++
+		}
+
+		for _, c := range r.Contributors {
+			outcome := replayOutcome(r.Data.Warno.Result.Victory)
+			if c.LocalPlayerKey != r.Data.Warno.LocalPlayerKey {
+				outcome = -outcome
+			}
+
+			if members[c.Member] == nil {
+				members[c.Member] = &poolStatsAccumulator{}
+				memberIds[c.Member] = make(map[string]struct{})
+			}
+			members[c.Member].add(r.Data, outcome)
+			if player, ok := r.Data.Warno.Players[c.LocalPlayerKey]; ok && player.PlayerUserId != "" {
+				memberIds[c.Member][player.PlayerUserId] = struct{}{}
+			}
+
+			if len(r.Contributors) == 1 {
+				team.add(r.Data, outcome)
+			}
+		}
+	}
+
+	stats.Team = team.result()
+
+	for member, acc := range members {
+		ids := make([]string, 0, len(memberIds[member]))
+		for id := range memberIds[member] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		stats.Members = append(stats.Members, MemberPoolStats{
+			Member:   member,
+			EugenIds: ids,
+			Stats:    acc.result(),
+		})
+	}
+
+	sort.Slice(stats.Members, func(i, j int) bool {
+		return stats.Members[i].Member < stats.Members[j].Member
+	})
+
+	return stats
+}
+
+// ExportReplayBundle writes the replays found in directories into a bundle that
+// other team members can import. The user picks the target file; an empty path
+// is returned when the dialog is cancelled.
+func (a *App) ExportReplayBundle(directories []string, member string, includeFiles bool) (string, error) {
+	if member == "" {
+		return "", errors.New("member name is required")
+	}
+
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export replays",
+		DefaultFilename: sanitizeFileName(member) + replayBundleExtension,
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Replay bundle (*" + replayBundleExtension + ")", Pattern: "*" + replayBundleExtension},
+		},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	bundle := ReplayBundle{
+		FormatVersion: replayBundleFormatVersion,
+		AppVersion:    version,
+		Member:        member,
+		ExportedAt:    time.Now().UTC(),
+	}
+	for _, replay := range getReplays(directories) {
+		bundle.Replays = append(bundle.Replays, ReplayBundleEntry{Data: replay})
+	}
+
+	if err := writeReplayBundle(path, bundle, includeFiles); err != nil {
+		return "", err
+	}
+
+	log.Printf("Exported %d replays for %s to %s", len(bundle.Replays), member, path)
+
+	return path, nil
+}
+
+// ImportReplayBundle lets the user pick a bundle and merges it into the team
+// pool. Games already in the pool are matched by UniqueSessionId.
+func (a *App) ImportReplayBundle() (TeamPoolImportResult, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Import replays",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Replay bundle (*" + replayBundleExtension + ")", Pattern: "*" + replayBundleExtension},
+		},
+	})
+	if err != nil || path == "" {
+		return TeamPoolImportResult{}, err
+	}
+
+	result, err := importReplayBundle(path)
+	if err != nil {
+		return result, err
+	}
+
+	log.Printf("Imported bundle from %s: member=%s imported=%d duplicates=%d", path, result.Member, result.Imported, result.Duplicates)
+
+	return result, nil
+}
+
+func (a *App) GetTeamPoolReplays() []PooledReplay {
+	teamPoolMu.Lock()
+	defer teamPoolMu.Unlock()
+
+	pool, err := loadTeamPool()
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return []PooledReplay{}
+	}
+
+	return pool.Replays
+}
+
+func (a *App) GetTeamPoolStats() TeamPoolStats {
+	teamPoolMu.Lock()
+	defer teamPoolMu.Unlock()
+
+	pool, err := loadTeamPool()
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return TeamPoolStats{}
+	}
+
+	return buildTeamPoolStats(pool)
+}
+
+// RemoveTeamPoolMember drops a member's contributions. Games only that member
+// contributed are removed together with their extracted replay files.
+func (a *App) RemoveTeamPoolMember(member string) error {
+	teamPoolMu.Lock()
+	defer teamPoolMu.Unlock()
+
+	pool, err := loadTeamPool()
+	if err != nil {
+		return err
+	}
+
+	kept := pool.Replays[:0]
+	for _, r := range pool.Replays {
+		contributors := r.Contributors[:0]
+		for _, c := range r.Contributors {
+			if c.Member != member {
+				contributors = append(contributors, c)
+			}
+		}
+		if len(contributors) == 0 {
+			continue
+		}
+		if r.Member == member {
+			// The extracted file lives in the removed member's directory.
+			r.Data.FilePath = ""
+		}
+		r.Contributors = contributors
+		r.Member = contributors[0].Member
+		kept = append(kept, r)
+	}
+	pool.Replays = kept
+
+	if err := saveTeamPool(pool); err != nil {
+		return err
+	}
+
+	poolDir, err := getTeamPoolDir()
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(filepath.Join(poolDir, "replays", sanitizeFileName(member)))
+}
@@ -1,32 +1,763 @@
 package main
 
 import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const releasesApiUrl = "https://api.github.com/repos/Kraku/warno-replays-analyser/releases"
+
+// Update channels. Pre-release also offers stable releases when they are the
+// newest version.
+const (
+	updateChannelStable     = "stable"
+	updateChannelPrerelease = "prerelease"
+)
+
+var releaseClient = &http.Client{Timeout: 30 * time.Second}
+
+type githubAsset struct {
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+	// Digest is "sha256:<hex>" on assets uploaded since GitHub started
+	// computing digests.
+	Digest string `json:"digest"`
+}
+
+type githubRelease struct {
+	TagName     string        `json:"tag_name"`
+	Name        string        `json:"name"`
+	Body        string        `json:"body"`
+	Draft       bool          `json:"draft"`
+	Prerelease  bool          `json:"prerelease"`
+	PublishedAt string        `json:"published_at"`
+	HTMLURL     string        `json:"html_url"`
+	Assets      []githubAsset `json:"assets"`
+}
+
+// ReleaseInfo is a published release as shown in the update dialog. Notes is
+// the release description in Markdown.
+type ReleaseInfo struct {
+	Version     string `json:"version"`
+	Name        string `json:"name"`
+	Notes       string `json:"notes"`
+	Prerelease  bool   `json:"prerelease"`
+	PublishedAt string `json:"publishedAt"`
+	URL         string `json:"url"`
+	AssetName   string `json:"assetName,omitempty"`
+	AssetSize   int64  `json:"assetSize,omitempty"`
+}
+
+// UpdateStatus tells whether a newer release exists on the configured channel
+// and which version, if any, is staged to be installed on the next restart.
+type UpdateStatus struct {
+	CurrentVersion  string       `json:"currentVersion"`
+	Channel         string       `json:"channel"`
+	Latest          *ReleaseInfo `json:"latest,omitempty"`
+	UpdateAvailable bool         `json:"updateAvailable"`
+	StagedVersion   string       `json:"stagedVersion,omitempty"`
+	// UpdateError explains why the last staged update could not be installed.
+	UpdateError string `json:"updateError,omitempty"`
+}
+
+// stagedUpdate is written next to the downloaded executable once its checksum
+// has been verified.
+type stagedUpdate struct {
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	SHA256  string `json:"sha256"`
+	// Installer is set when Path is the NSIS installer rather than the app
+	// executable; it is run instead of swapping files.
+	Installer bool `json:"installer,omitempty"`
+}
+
+// failedUpdate records why installing a staged update failed, so the next
+// start can tell the user.
+type failedUpdate struct {
+	Version string `json:"version"`
+	Error   string `json:"error"`
+}
+
+type semver struct {
+	major, minor, patch int
+	pre                 []string
+}
+
+// parseSemver reads versions like "v2.1.0" or "2.1.0-beta.2". Build metadata
+// after "+" is ignored and missing minor or patch numbers count as 0.
+func parseSemver(s string) (semver, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+
+	var v semver
+	core := s
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		core = s[:i]
+		v.pre = strings.Split(s[i+1:], ".")
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return semver{}, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*int{&v.major, &v.minor, &v.patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return semver{}, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+
+	return v, nil
+}
+
+// compareSemver returns -1, 0 or 1 following the semver precedence rules: a
+// pre-release sorts before its release, numeric identifiers compare as
+// numbers and before alphanumeric ones.
+func compareSemver(a, b semver) int {
+	for _, d := range []int{a.major - b.major, a.minor - b.minor, a.patch - b.patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+
+	switch {
+	case len(a.pre) == 0 && len(b.pre) == 0:
+		return 0
+	case len(a.pre) == 0:
+		return 1
+	case len(b.pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a.pre) && i < len(b.pre); i++ {
+		x, errX := strconv.Atoi(a.pre[i])
+		y, errY := strconv.Atoi(b.pre[i])
+		switch {
+		case errX == nil && errY == nil:
+			if x != y {
+				return sign(x - y)
+			}
+		case errX == nil:
+			return -1
+		case errY == nil:
+			return 1
+		default:
+			if c := strings.Compare(a.pre[i], b.pre[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return sign(len(a.pre) - len(b.pre))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// isNewerVersion reports whether latest is a higher version than current.
+// Unparseable versions are never considered newer.
+func isNewerVersion(current, latest string) bool {
+	c, err := parseSemver(current)
+	if err != nil {
+		return false
+	}
+	l, err := parseSemver(latest)
+	if err != nil {
+		return false
+	}
+	return compareSemver(l, c) > 0
+}
+
+func fetchGithubJSON(url string, out any) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := releaseClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching releases: HTTP error: %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// latestRelease picks the highest version on channel. Drafts are skipped, and
+// so are pre-releases on the stable channel.
+func latestRelease(releases []githubRelease, channel string) (githubRelease, bool) {
+	var best githubRelease
+	var bestVersion semver
+	for _, r := range releases {
+		if r.Draft || (r.Prerelease && channel != updateChannelPrerelease) {
+			continue
+		}
+		v, err := parseSemver(r.TagName)
+		if err != nil {
+			continue
+		}
+		if best.TagName == "" || compareSemver(v, bestVersion) > 0 {
+			best, bestVersion = r, v
+		}
+	}
+	return best, best.TagName != ""
+}
+
+// fetchLatestRelease returns the newest release on channel among the recent
+// releases.
+func fetchLatestRelease(channel string) (githubRelease, error) {
+	var releases []githubRelease
+	if err := fetchGithubJSON(releasesApiUrl+"?per_page=30", &releases); err != nil {
+		return githubRelease{}, err
+	}
+
+	release, ok := latestRelease(releases, channel)
+	if !ok {
+		return githubRelease{}, errors.New("no releases found")
+	}
+	return release, nil
+}
+
+// appAssetName is the release asset holding the bare app executable, as
+// named by outputfilename in wails.json.
+const appAssetName = "warno-replays-analyser.exe"
+
+// updateAsset returns the asset to update from: the app executable when
+// preferInstaller is false and it is published, otherwise the NSIS installer.
+// Other executables are never picked, since swapping one in would break the
+// install.
+func updateAsset(release githubRelease, preferInstaller bool) (asset githubAsset, installer bool, ok bool) {
+	var app, setup *githubAsset
+	for i, a := range release.Assets {
+		name := strings.ToLower(a.Name)
+		switch {
+		case name == appAssetName:
+			app = &release.Assets[i]
+		case strings.HasSuffix(name, ".exe") && strings.Contains(name, "installer"):
+			setup = &release.Assets[i]
+		}
+	}
+
+	switch {
+	case app != nil && (!preferInstaller || setup == nil):
+		return *app, false, true
+	case setup != nil:
+		return *setup, true, true
+	}
+	return githubAsset{}, false, false
+}
+
+func releaseInfo(release githubRelease) *ReleaseInfo {
+	info := &ReleaseInfo{
+		Version:     release.TagName,
+		Name:        release.Name,
+		Notes:       release.Body,
+		Prerelease:  release.Prerelease,
+		PublishedAt: release.PublishedAt,
+		URL:         release.HTMLURL,
+	}
+	if asset, _, ok := updateAsset(release, false); ok {
+		info.AssetName = asset.Name
+		info.AssetSize = asset.Size
+	}
+	return info
+}
+
+// expectedChecksum returns the SHA-256 published for asset, either as the
+// asset digest or in a checksum file attached to the release.
+func expectedChecksum(release githubRelease, asset githubAsset) (string, error) {
+	if digest, ok := strings.CutPrefix(asset.Digest, "sha256:"); ok {
+		return strings.ToLower(digest), nil
+	}
+
+	for _, a := range release.Assets {
+		name := strings.ToLower(a.Name)
+		if name != strings.ToLower(asset.Name)+".sha256" && name != "checksums.txt" && name != "sha256sums" {
+			continue
+		}
+
+		sum, err := readChecksumFile(a, asset.Name)
+		if err != nil {
+			return "", err
+		}
+		if sum != "" {
+			return sum, nil
+		}
+	}
+
+	return "", fmt.Errorf("release %s publishes no checksum for %s", release.TagName, asset.Name)
+}
+
+// readChecksumFile downloads a checksum asset and returns the hash listed for
+// assetName, or "" when it lists none. Lines are "<hex>  <file name>"; a
+// .sha256 file may hold the hash only.
+func readChecksumFile(checksums githubAsset, assetName string) (string, error) {
+	resp, err := releaseClient.Get(checksums.BrowserDownloadURL)
+	if err != nil {
+		return "", fmt.Errorf("downloading %s: %w", checksums.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading %s: HTTP error: %d", checksums.Name, resp.StatusCode)
+	}
+
+	return parseChecksumFile(resp.Body, checksums.Name, assetName)
+}
+
+// parseChecksumFile returns the hash listed for assetName in the checksum file
+// named fileName, or "" when it lists none.
+func parseChecksumFile(r io.Reader, fileName, assetName string) (string, error) {
+	hashOnly := strings.HasSuffix(strings.ToLower(fileName), ".sha256")
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 1 && hashOnly {
+			return strings.ToLower(fields[0]), nil
+		}
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == assetName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+func getUpdatesDir() (string, error) {
+	return getLocalAppDataDir("warno-replays-analyser", "updates")
+}
+
+func stagedUpdatePath() (string, error) {
+	dir, err := getUpdatesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "staged.json"), nil
+}
+
+func failedUpdatePath() (string, error) {
+	dir, err := getUpdatesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "failed.json"), nil
+}
+
+func readFailedUpdate() (failedUpdate, bool) {
+	path, err := failedUpdatePath()
+	if err != nil {
+		return failedUpdate{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return failedUpdate{}, false
+	}
+	var failed failedUpdate
+	if err := json.Unmarshal(data, &failed); err != nil {
+		return failedUpdate{}, false
+	}
+	return failed, true
+}
+
+func recordFailedUpdate(version string, cause error) {
+	log.Printf("Error installing update %s: %v", version, cause)
+
+	path, err := failedUpdatePath()
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(failedUpdate{Version: version, Error: cause.Error()})
+	if err != nil {
+		return
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		log.Printf("Error recording failed update: %v", err)
+	}
+}
+
+func clearFailedUpdate() {
+	if path, err := failedUpdatePath(); err == nil {
+		os.Remove(path)
+	}
+}
+
+func readStagedUpdate() (stagedUpdate, bool) {
+	path, err := stagedUpdatePath()
+	if err != nil {
+		return stagedUpdate{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return stagedUpdate{}, false
+	}
+
+	var staged stagedUpdate
+	if err := json.Unmarshal(data, &staged); err != nil || staged.Path == "" {
+		return stagedUpdate{}, false
+	}
+	return staged, true
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// downloadAsset downloads asset into dir and checks it against checksum
+// before returning its path.
+func downloadAsset(asset githubAsset, checksum, dir string) (string, error) {
+	resp, err := releaseClient.Get(asset.BrowserDownloadURL)
+	if err != nil {
+		return "", fmt.Errorf("downloading %s: %w", asset.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading %s: HTTP error: %d", asset.Name, resp.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp(dir, "download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("downloading %s: %w", asset.Name, err)
+	}
+
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != checksum {
+		return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", asset.Name, checksum, sum)
+	}
+
+	path := filepath.Join(dir, sanitizeFileName(asset.Name))
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (a *App) updateChannel() string {
+	settings, err := a.GetSettings()
+	if err != nil || settings.UpdateChannel == "" {
+		return updateChannelStable
+	}
+	return settings.UpdateChannel
+}
+
 func (a *App) GetAppVersions() []string {
 	latestVersion := ""
 
-	resp, err := http.Get("https://api.github.com/repos/Kraku/warno-replays-analyser/releases/latest")
+	release, err := fetchLatestRelease(a.updateChannel())
 	if err != nil {
 		log.Printf("Error fetching latest version: %v", err)
 	} else {
-		defer resp.Body.Close()
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			log.Printf("Error reading response body: %v", err)
-		} else {
-			var result map[string]any
-			if err := json.Unmarshal(body, &result); err != nil {
-				log.Printf("Error unmarshaling JSON: %v", err)
-			} else if tagName, ok := result["tag_name"].(string); ok {
-				latestVersion = tagName
-			}
-		}
+		latestVersion = release.TagName
 	}
 
 	return []string{version, latestVersion}
 }
+
+func (a *App) checkForUpdate() (UpdateStatus, githubRelease, error) {
+	status := UpdateStatus{CurrentVersion: version, Channel: a.updateChannel()}
+	if staged, ok := readStagedUpdate(); ok {
+		status.StagedVersion = staged.Version
+	}
+	if failed, ok := readFailedUpdate(); ok {
+		status.UpdateError = fmt.Sprintf("Installing %s failed: %s", failed.Version, failed.Error)
+	}
+
+	release, err := fetchLatestRelease(status.Channel)
+	if err != nil {
+		return status, githubRelease{}, err
+	}
+	status.Latest = releaseInfo(release)
+	status.UpdateAvailable = isNewerVersion(version, release.TagName)
+
+	return status, release, nil
+}
+
+// CheckForUpdate fetches the newest release of the configured channel with
+// its release notes.
+func (a *App) CheckForUpdate() (UpdateStatus, error) {
+	status, _, err := a.checkForUpdate()
+	return status, err
+}
+
+// DownloadUpdate downloads the newest release, verifies its SHA-256 checksum
+// and stages it; the executable is replaced when the app closes.
+func (a *App) DownloadUpdate() (UpdateStatus, error) {
+	status, release, err := a.checkForUpdate()
+	if err != nil {
+		return status, err
+	}
+	if !status.UpdateAvailable {
+		return status, fmt.Errorf("%s is already the latest version", version)
+	}
+	if status.StagedVersion == release.TagName {
+		return status, nil
+	}
+
+	// Without write access to the app folder, as under Program Files, only
+	// the installer can update the app.
+	exe, err := currentExecutable()
+	if err != nil {
+		return status, err
+	}
+	preferInstaller := !dirWritable(filepath.Dir(exe))
+
+	asset, installer, ok := updateAsset(release, preferInstaller)
+	if !ok {
+		return status, fmt.Errorf("release %s has no Windows executable or installer", release.TagName)
+	}
+	if !installer && preferInstaller {
+		return status, fmt.Errorf("cannot write to %s and release %s has no installer", filepath.Dir(exe), release.TagName)
+	}
+	checksum, err := expectedChecksum(release, asset)
+	if err != nil {
+		return status, err
+	}
+
+	dir, err := getUpdatesDir()
+	if err != nil {
+		return status, err
+	}
+	if err := a.CancelStagedUpdate(); err != nil {
+		return status, err
+	}
+	path, err := downloadAsset(asset, checksum, dir)
+	if err != nil {
+		return status, err
+	}
+
+	data, err := json.Marshal(stagedUpdate{Version: release.TagName, Path: path, SHA256: checksum, Installer: installer})
+	if err != nil {
+		return status, err
+	}
+	stagedPath, err := stagedUpdatePath()
+	if err != nil {
+		return status, err
+	}
+	if err := writeFileAtomic(stagedPath, data, 0644); err != nil {
+		return status, err
+	}
+
+	clearFailedUpdate()
+	status.StagedVersion = release.TagName
+	status.UpdateError = ""
+	return status, nil
+}
+
+// CancelStagedUpdate discards a downloaded update.
+func (a *App) CancelStagedUpdate() error {
+	staged, ok := readStagedUpdate()
+	if ok {
+		if err := os.Remove(staged.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing staged update: %w", err)
+		}
+	}
+
+	path, err := stagedUpdatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing staged update: %w", err)
+	}
+	return nil
+}
+
+func currentExecutable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("locating executable: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return "", fmt.Errorf("locating executable: %w", err)
+	}
+	return exe, nil
+}
+
+func dirWritable(dir string) bool {
+	f, err := os.CreateTemp(dir, ".write-test-*")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
+
+// copyFile copies src to dst, which must not exist yet.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+// replaceExecutable copies the update next to exe first, since the updates
+// folder may be on another volume, then swaps the two within the folder.
+// Windows lets a running executable be renamed but not overwritten, so the
+// current one is moved aside and removed on the next start.
+func replaceExecutable(exe, update string) error {
+	next := exe + ".new"
+	os.Remove(next)
+	if err := copyFile(update, next); err != nil {
+		return fmt.Errorf("copying update next to %s: %w", exe, err)
+	}
+
+	old := exe + ".old"
+	os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		os.Remove(next)
+		return fmt.Errorf("moving %s aside: %w", exe, err)
+	}
+	if err := os.Rename(next, exe); err != nil {
+		os.Rename(old, exe)
+		os.Remove(next)
+		return fmt.Errorf("replacing %s: %w", exe, err)
+	}
+	return nil
+}
+
+// applyStagedUpdate installs the staged update as the app closes: the
+// executable is swapped, or the installer is started. The staged update is
+// dropped whether or not this works; a failure is shown on the next start.
+func (a *App) applyStagedUpdate() {
+	staged, ok := readStagedUpdate()
+	if !ok {
+		return
+	}
+	if !isNewerVersion(version, staged.Version) {
+		a.CancelStagedUpdate()
+		return
+	}
+
+	err := installStagedUpdate(staged)
+	if staged.Installer && err == nil {
+		// The running installer still needs its file; it is removed with the
+		// other leftovers on the next start.
+		if path, err := stagedUpdatePath(); err == nil {
+			os.Remove(path)
+		}
+		return
+	}
+	a.CancelStagedUpdate()
+
+	if err != nil {
+		recordFailedUpdate(staged.Version, err)
+		return
+	}
+	clearFailedUpdate()
+	log.Printf("Installed update %s", staged.Version)
+}
+
+func installStagedUpdate(staged stagedUpdate) error {
+	sum, err := fileSHA256(staged.Path)
+	if err != nil {
+		return fmt.Errorf("reading staged update: %w", err)
+	}
+	if sum != staged.SHA256 {
+		return errors.New("the downloaded file no longer matches its checksum")
+	}
+
+	if staged.Installer {
+		if err := exec.Command(staged.Path).Start(); err != nil {
+			return fmt.Errorf("starting installer: %w", err)
+		}
+		return nil
+	}
+
+	exe, err := currentExecutable()
+	if err != nil {
+		return err
+	}
+	return replaceExecutable(exe, staged.Path)
+}
+
+// removeUpdateLeftovers deletes the executable replaced by the last update and
+// downloads no longer staged.
+func removeUpdateLeftovers() {
+	if exe, err := currentExecutable(); err == nil {
+		if err := os.Remove(exe + ".old"); err != nil && !os.IsNotExist(err) {
+			log.Printf("Error removing previous executable: %v", err)
+		}
+	}
+
+	dir, err := getUpdatesDir()
+	if err != nil {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	staged, _ := readStagedUpdate()
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if e.IsDir() || strings.HasSuffix(e.Name(), ".json") || sameFolder(path, staged.Path) {
+			continue
+		}
+		os.Remove(path)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompareSemver(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3+build.5", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-beta.11", "1.0.0-rc.1", -1},
+		{"1.0.1-beta.1", "1.0.0", 1},
+	}
+
+	for _, tt := range tests {
+		a, err := parseSemver(tt.a)
+		if err != nil {
+			t.Fatalf("parseSemver(%q): %v", tt.a, err)
+		}
+		b, err := parseSemver(tt.b)
+		if err != nil {
+			t.Fatalf("parseSemver(%q): %v", tt.b, err)
+		}
+
+		if got := compareSemver(a, b); got != tt.want {
+			t.Errorf("compareSemver(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := compareSemver(b, a); got != -tt.want {
+			t.Errorf("compareSemver(%s, %s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestParseSemverRejectsInvalid(t *testing.T) {
+	for _, s := range []string{"", "latest", "1.2.3.4", "1.x.0", "1.-2.0"} {
+		if _, err := parseSemver(s); err == nil {
+			t.Errorf("parseSemver(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.4.0", "v1.5.0", true},
+		{"v1.5.0", "v1.5.0", false},
+		{"v1.5.0", "v1.4.9", false},
+		{"v1.5.0-beta.1", "v1.5.0", true},
+		{"v1.5.0", "v1.5.0-beta.1", false},
+		{"dev", "v1.5.0", false},
+		{"v1.5.0", "nightly", false},
+	}
+
+	for _, tt := range tests {
+		if got := isNewerVersion(tt.current, tt.latest); got != tt.want {
+			t.Errorf("isNewerVersion(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestLatestRelease(t *testing.T) {
+	releases := []githubRelease{
+		{TagName: "v1.4.0"},
+		{TagName: "v1.6.0", Draft: true},
+		{TagName: "v1.5.0-beta.2", Prerelease: true},
+		{TagName: "v1.5.0-beta.10", Prerelease: true},
+		{TagName: "not-a-version"},
+		{TagName: "v1.3.2"},
+	}
+
+	tests := []struct {
+		channel string
+		want    string
+	}{
+		{updateChannelStable, "v1.4.0"},
+		{"", "v1.4.0"},
+		{updateChannelPrerelease, "v1.5.0-beta.10"},
+	}
+
+	for _, tt := range tests {
+		got, ok := latestRelease(releases, tt.channel)
+		if !ok || got.TagName != tt.want {
+			t.Errorf("latestRelease(%q) = %q, %v, want %q", tt.channel, got.TagName, ok, tt.want)
+		}
+	}
+
+	// A stable release newer than every pre-release is offered on both channels.
+	releases = append(releases, githubRelease{TagName: "v1.5.0"})
+	if got, _ := latestRelease(releases, updateChannelPrerelease); got.TagName != "v1.5.0" {
+		t.Errorf("latestRelease(prerelease) = %q, want v1.5.0", got.TagName)
+	}
+
+	if _, ok := latestRelease([]githubRelease{{TagName: "v2.0.0-rc.1", Prerelease: true}}, updateChannelStable); ok {
+		t.Error("latestRelease(stable) found a release among pre-releases only")
+	}
+}
+
+func TestUpdateAsset(t *testing.T) {
+	app := githubAsset{Name: "warno-replays-analyser.exe"}
+	setup := githubAsset{Name: "warno-replays-analyser-amd64-installer.exe"}
+	other := githubAsset{Name: "warno-replays-analyser-debug.exe"}
+	checksums := githubAsset{Name: "checksums.txt"}
+
+	tests := []struct {
+		name            string
+		assets          []githubAsset
+		preferInstaller bool
+		want            string
+		wantInstaller   bool
+		wantOK          bool
+	}{
+		{"app and installer", []githubAsset{setup, app, checksums}, false, app.Name, false, true},
+		{"installer preferred", []githubAsset{app, setup}, true, setup.Name, true, true},
+		{"installer only", []githubAsset{setup, checksums}, false, setup.Name, true, true},
+		{"app only, installer preferred", []githubAsset{app}, true, app.Name, false, true},
+		{"app name case", []githubAsset{{Name: "Warno-Replays-Analyser.exe"}}, false, "Warno-Replays-Analyser.exe", false, true},
+		{"other executables", []githubAsset{other, checksums}, false, "", false, false},
+		{"no assets", nil, false, "", false, false},
+	}
+
+	for _, tt := range tests {
+		asset, installer, ok := updateAsset(githubRelease{Assets: tt.assets}, tt.preferInstaller)
+		if asset.Name != tt.want || installer != tt.wantInstaller || ok != tt.wantOK {
+			t.Errorf("%s: got %q, installer=%v, ok=%v; want %q, installer=%v, ok=%v",
+				tt.name, asset.Name, installer, ok, tt.want, tt.wantInstaller, tt.wantOK)
+		}
+	}
+}
+
+func TestParseChecksumFile(t *testing.T) {
+	const hash = "9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08"
+	const asset = "warno-replays-analyser.exe"
+
+	tests := []struct {
+		name     string
+		fileName string
+		content  string
+		want     string
+	}{
+		{"sha256sum format", "checksums.txt", "0000  other.exe\n" + hash + "  " + asset + "\n", strings.ToLower(hash)},
+		{"binary marker", "SHA256SUMS", hash + " *" + asset + "\n", strings.ToLower(hash)},
+		{"hash only", asset + ".sha256", hash + "\n", strings.ToLower(hash)},
+		{"hash only outside .sha256", "checksums.txt", hash + "\n", ""},
+		{"not listed", "checksums.txt", hash + "  " + asset + ".zip\n", ""},
+		{"empty", "checksums.txt", "", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := parseChecksumFile(strings.NewReader(tt.content), tt.fileName, asset)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExpectedChecksum(t *testing.T) {
+	const hash = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/checksums.txt":
+			w.Write([]byte(hash + "  warno-replays-analyser.exe\n"))
+		case "/empty.sha256":
+			w.Write([]byte("\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	app := githubAsset{Name: "warno-replays-analyser.exe"}
+
+	withDigest := app
+	withDigest.Digest = "sha256:" + strings.ToUpper(hash)
+	if got, err := expectedChecksum(githubRelease{}, withDigest); err != nil || got != hash {
+		t.Errorf("digest: got %q, %v; want %q", got, err, hash)
+	}
+
+	release := githubRelease{
+		TagName: "v1.5.0",
+		Assets: []githubAsset{
+			app,
+			{Name: "warno-replays-analyser.exe.sha256", BrowserDownloadURL: server.URL + "/empty.sha256"},
+			{Name: "checksums.txt", BrowserDownloadURL: server.URL + "/checksums.txt"},
+		},
+	}
+	if got, err := expectedChecksum(release, app); err != nil || got != hash {
+		t.Errorf("checksum file: got %q, %v; want %q", got, err, hash)
+	}
+
+	release.Assets = release.Assets[:1]
+	if _, err := expectedChecksum(release, app); err == nil {
+		t.Error("expected an error for a release without checksums")
+	}
+
+	release.Assets = []githubAsset{app, {Name: "checksums.txt", BrowserDownloadURL: server.URL + "/missing"}}
+	if _, err := expectedChecksum(release, app); err == nil {
+		t.Error("expected an error when the checksum file cannot be downloaded")
+	}
+}
@@ -24,8 +24,18 @@ func (a *App) watchFolder(path string) {
 	for {
 		select {
 		case event := <-watcher.Events:
+			// Renames, moves and deletes made from the app are reported by
+			// the operation itself.
+			if isSelfChange(event.Name) {
+				continue
+			}
 			if event.Op&fsnotify.Create == fsnotify.Create {
 				runtime.EventsEmit(a.ctx, "replay-file-added", event.Name)
+				a.scheduleSessionUpdate()
+			}
+			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				removeReplayCache(event.Name)
+				runtime.EventsEmit(a.ctx, "replay-file-removed", event.Name)
 			}
 		case err := <-watcher.Errors:
 			log.Println("Watcher error:", err)
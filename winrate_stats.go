@@ -0,0 +1,193 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// wilsonZ is the normal quantile for a 95% confidence interval.
+const wilsonZ = 1.96
+
+const (
+	WinrateConfidenceLow    = "low"
+	WinrateConfidenceMedium = "medium"
+	WinrateConfidenceHigh   = "high"
+)
+
+const (
+	AnalyticsSortGames      = "games"
+	AnalyticsSortWinRate    = "winRate"
+	AnalyticsSortWinRateLow = "winRateLow"
+)
+
+// Games needed before a winrate is flagged as medium or high confidence.
+const (
+	winrateMediumConfidenceGames = 30
+	winrateHighConfidenceGames   = 100
+)
+
+type winrateEstimate struct {
+	low          float64
+	high         float64
+	drawAdjusted float64
+	confidence   string
+}
+
+// wilsonInterval returns the Wilson score interval for wins out of n games as
+// fractions in 0..1.
+func wilsonInterval(wins, n int) (float64, float64) {
+	if n <= 0 {
+		return 0, 0
+	}
+
+	p := float64(wins) / float64(n)
+	nf := float64(n)
+	z2 := wilsonZ * wilsonZ
+
+	center := (p + z2/(2*nf)) / (1 + z2/nf)
+	margin := wilsonZ * math.Sqrt(p*(1-p)/nf+z2/(4*nf*nf)) / (1 + z2/nf)
+
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// estimateWinrate computes the interval over decisive games, the winrate with
+// draws counted as half a win, and a confidence flag based on sample size.
+func estimateWinrate(wins, losses, draws int) winrateEstimate {
+	var e winrateEstimate
+
+	e.low, e.high = wilsonInterval(wins, wins+losses)
+
+	if games := wins + losses + draws; games > 0 {
+		e.drawAdjusted = (float64(wins) + 0.5*float64(draws)) / float64(games)
+	}
+
+	switch games := wins + losses + draws; {
+	case games >= winrateHighConfidenceGames:
+		e.confidence = WinrateConfidenceHigh
+	case games >= winrateMediumConfidenceGames:
+		e.confidence = WinrateConfidenceMedium
+	default:
+		e.confidence = WinrateConfidenceLow
+	}
+
+	return e
+}
+
+func (r *DivisionWinrateRow) annotate() {
+	e := estimateWinrate(r.Wins, r.Losses, r.Draws)
+	r.WinRateLow, r.WinRateHigh, r.DrawAdjustedWinRate, r.Confidence = e.low, e.high, e.drawAdjusted, e.confidence
+}
+
+func (r *DivisionVsOpponentStats) annotate() {
+	e := estimateWinrate(r.Wins, r.Losses, r.Draws)
+	r.WinRateLow, r.WinRateHigh, r.DrawAdjustedWinRate, r.Confidence = e.low, e.high, e.drawAdjusted, e.confidence
+}
+
+func (r *DivisionOnMapVsOpponentStats) annotate() {
+	e := estimateWinrate(r.Wins, r.Losses, r.Draws)
+	r.WinRateLow, r.WinRateHigh, r.DrawAdjustedWinRate, r.Confidence = e.low, e.high, e.drawAdjusted, e.confidence
+}
+
+func (r *DivisionVsWinrateRow) annotate() {
+	e := estimateWinrate(r.Wins, r.Losses, r.Draws)
+	r.WinRateLow, r.WinRateHigh, r.DrawAdjustedWinRate, r.Confidence = e.low, e.high, e.drawAdjusted, e.confidence
+}
+
+func (r *DivisionOnMapWinrateRow) annotate() {
+	e := estimateWinrate(r.Wins, r.Losses, r.Draws)
+	r.WinRateLow, r.WinRateHigh, r.DrawAdjustedWinRate, r.Confidence = e.low, e.high, e.drawAdjusted, e.confidence
+}
+
+func (r *DivisionOnMapVsWinrateRow) annotate() {
+	e := estimateWinrate(r.Wins, r.Losses, r.Draws)
+	r.WinRateLow, r.WinRateHigh, r.DrawAdjustedWinRate, r.Confidence = e.low, e.high, e.drawAdjusted, e.confidence
+}
+
+// annotateRankedAnalytics fills the interval, draw-adjusted and confidence
+// fields of every row in the response.
+func annotateRankedAnalytics(resp *RankedReplaysAnalyticsResponse) {
+	for i := range resp.Divisions {
+		resp.Divisions[i].annotate()
+	}
+	for i := range resp.DivisionVs {
+		for j := range resp.DivisionVs[i].Opponents {
+			resp.DivisionVs[i].Opponents[j].annotate()
+		}
+	}
+	for i := range resp.DivisionOnMap {
+		for j := range resp.DivisionOnMap[i].Divisions {
+			resp.DivisionOnMap[i].Divisions[j].annotate()
+		}
+	}
+	for i := range resp.DivisionOnMapVs {
+		for j := range resp.DivisionOnMapVs[i].Divisions {
+			for k := range resp.DivisionOnMapVs[i].Divisions[j].Opponents {
+				resp.DivisionOnMapVs[i].Divisions[j].Opponents[k].annotate()
+			}
+		}
+	}
+}
+
+// winrateSortKey returns the value rows are ordered by for sortBy. Unknown
+// values fall back to the number of games.
+func winrateSortKey(sortBy string, games int, winRate, winRateLow float64) float64 {
+	switch sortBy {
+	case AnalyticsSortWinRate:
+		return winRate
+	case AnalyticsSortWinRateLow:
+		return winRateLow
+	default:
+		return float64(games)
+	}
+}
+
+// sortedRankedAnalytics returns a copy of resp with every row list ordered by
+// sortBy, descending. An empty sortBy returns resp unchanged.
+func sortedRankedAnalytics(resp RankedReplaysAnalyticsResponse, sortBy string) RankedReplaysAnalyticsResponse {
+	if sortBy == "" {
+		return resp
+	}
+
+	sortDivisions := func(rows []DivisionWinrateRow) []DivisionWinrateRow {
+		rows = append([]DivisionWinrateRow(nil), rows...)
+		sort.SliceStable(rows, func(i, j int) bool {
+			return winrateSortKey(sortBy, rows[i].Games, rows[i].WinRate, rows[i].WinRateLow) >
+				winrateSortKey(sortBy, rows[j].Games, rows[j].WinRate, rows[j].WinRateLow)
+		})
+		return rows
+	}
+
+	out := resp
+	out.Divisions = sortDivisions(resp.Divisions)
+
+	out.DivisionVs = make([]DivisionVsGroup, len(resp.DivisionVs))
+	for i, group := range resp.DivisionVs {
+		opponents := append([]DivisionVsOpponentStats(nil), group.Opponents...)
+		sort.SliceStable(opponents, func(a, b int) bool {
+			return winrateSortKey(sortBy, opponents[a].Games, opponents[a].WinRate, opponents[a].WinRateLow) >
+				winrateSortKey(sortBy, opponents[b].Games, opponents[b].WinRate, opponents[b].WinRateLow)
+		})
+		out.DivisionVs[i] = DivisionVsGroup{Division: group.Division, Opponents: opponents}
+	}
+
+	out.DivisionOnMap = make([]DivisionOnMapGroup, len(resp.DivisionOnMap))
+	for i, group := range resp.DivisionOnMap {
+		out.DivisionOnMap[i] = DivisionOnMapGroup{Map: group.Map, Divisions: sortDivisions(group.Divisions)}
+	}
+
+	out.DivisionOnMapVs = make([]DivisionOnMapVsGroup, len(resp.DivisionOnMapVs))
+	for i, group := range resp.DivisionOnMapVs {
+		divisions := make([]DivisionOnMapVsDivisionGroup, len(group.Divisions))
+		for j, division := range group.Divisions {
+			opponents := append([]DivisionOnMapVsOpponentStats(nil), division.Opponents...)
+			sort.SliceStable(opponents, func(a, b int) bool {
+				return winrateSortKey(sortBy, opponents[a].Games, opponents[a].WinRate, opponents[a].WinRateLow) >
+					winrateSortKey(sortBy, opponents[b].Games, opponents[b].WinRate, opponents[b].WinRateLow)
+			})
+			divisions[j] = DivisionOnMapVsDivisionGroup{Division: division.Division, Opponents: opponents}
+		}
+		out.DivisionOnMapVs[i] = DivisionOnMapVsGroup{Map: group.Map, Divisions: divisions}
+	}
+
+	return out
+}